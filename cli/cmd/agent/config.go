@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect and validate config/ai-agent.yaml",
+}
+
+// resolveConfigPath returns args[0] if given, else config/ai-agent.yaml (or
+// ../config/ai-agent.yaml, matching findConfigYAML's fallback elsewhere in
+// this CLI) if that doesn't exist in the cwd.
+func resolveConfigPath(args []string) string {
+	if len(args) == 1 {
+		return args[0]
+	}
+	path := "config/ai-agent.yaml"
+	if _, err := os.Stat(path); err != nil {
+		path = "../config/ai-agent.yaml"
+	}
+	return path
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate [path]",
+	Short: "Validate a config file against the embedded schema and cross-field rules",
+	Long: `Validates config/ai-agent.yaml (or the given path) against the embedded
+JSON Schema (structure, types, required fields, enums) plus cross-field
+checks a schema can't express: sample rate alignment, default_provider
+referencing an enabled provider, and barge-in protection ranges.
+
+A config may pin an older schema revision with a top-level "$schema" key;
+see 'agent config schema'.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := resolveConfigPath(args)
+
+		result, err := config.NewValidator(path).Validate()
+		if err != nil {
+			return err
+		}
+
+		for _, p := range result.Passed {
+			fmt.Printf("  ✅ %s\n", p)
+		}
+		for _, w := range result.Warnings {
+			fmt.Printf("  ⚠️  %s\n", w)
+		}
+		for _, e := range result.Errors {
+			fmt.Printf("  ❌ %s\n", e)
+		}
+
+		if len(result.Errors) > 0 {
+			return fmt.Errorf("%d error(s) found in %s", len(result.Errors), path)
+		}
+		return nil
+	},
+}
+
+var configSchemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the embedded config JSON Schema",
+	Long: `Prints the JSON Schema config/ai-agent.yaml is validated against, for
+pointing an editor's YAML language server at it for auto-completion.
+
+Use --version to print an older pinned revision (see the "$schema" key
+documented in 'agent config validate --help').`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		version, _ := cmd.Flags().GetString("version")
+		data, err := config.RawSchema(version)
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	},
+}
+
+var configFixCmd = &cobra.Command{
+	Use:   "fix [path]",
+	Short: "Apply automatic fixes for common config issues",
+	Long: `Repairs legacy key names (input_sample_rate_hz -> provider_input_sample_rate_hz,
+model -> llm_model for google_live), deprecated model ids, misaligned
+input/output sample rates, an out-of-range post_tts_end_protection_ms, and a
+missing contexts: scaffold.
+
+--dry-run prints a unified diff without writing the file. --interactive
+prompts per fix. A timestamped .bak of the original is written before any
+in-place write, for rollback.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := resolveConfigPath(args)
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		interactive, _ := cmd.Flags().GetBool("interactive")
+
+		opts := config.FixOptions{DryRun: dryRun, Backup: true}
+		if interactive {
+			reader := bufio.NewReader(os.Stdin)
+			opts.Confirm = func(fix config.Fix) bool {
+				fmt.Printf("  Apply: %s? [Y/n]: ", fix.Description)
+				input, _ := reader.ReadString('\n')
+				input = strings.ToLower(strings.TrimSpace(input))
+				return input == "" || input == "y" || input == "yes"
+			}
+		}
+
+		fixes, diff, err := config.NewValidator(path).AutoFixWithOptions(opts)
+		if err != nil {
+			return err
+		}
+		if len(fixes) == 0 {
+			fmt.Println("  No fixes to apply")
+			return nil
+		}
+
+		for _, f := range fixes {
+			fmt.Printf("  - %s\n", f.Description)
+		}
+		if dryRun {
+			fmt.Println()
+			fmt.Print(diff)
+			fmt.Println("  (dry run: no changes written)")
+		} else {
+			fmt.Printf("  %d fix(es) applied to %s (backup written alongside it)\n", len(fixes), path)
+		}
+		return nil
+	},
+}
+
+func init() {
+	configSchemaCmd.Flags().String("version", "", "schema revision to print (defaults to the current one)")
+	configFixCmd.Flags().Bool("dry-run", false, "print a unified diff instead of writing the file")
+	configFixCmd.Flags().Bool("interactive", false, "prompt to accept/reject each fix")
+	configCmd.AddCommand(configValidateCmd, configSchemaCmd, configFixCmd)
+	rootCmd.AddCommand(configCmd)
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/container"
+	"github.com/spf13/cobra"
+)
+
+var containersCmd = &cobra.Command{
+	Use:   "containers",
+	Short: "Inspect and rebuild the agent's containers",
+}
+
+var containersStatusCmd = &cobra.Command{
+	Use:   "status <name>",
+	Short: "Show a container's running/health state and image digest",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		m, err := engineManager(cmd)
+		if err != nil {
+			return err
+		}
+
+		status, err := m.Status(context.Background(), args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("engine:  %s\n", m.Engine())
+		fmt.Printf("name:    %s\n", status.Name)
+		fmt.Printf("running: %v\n", status.Running)
+		if status.Health != "" {
+			fmt.Printf("health:  %s\n", status.Health)
+		}
+		if status.ImageID != "" {
+			fmt.Printf("image:   %s\n", status.ImageID)
+		}
+		return nil
+	},
+}
+
+var containersRebuildCmd = &cobra.Command{
+	Use:   "rebuild <service>",
+	Short: "Build and recreate one compose service, skipping it if already current",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		m, err := engineManager(cmd)
+		if err != nil {
+			return err
+		}
+		service := args[0]
+		ctx := context.Background()
+
+		if stale, err := container.NeedsRebuild(ctx, m, service, container.ImageTag(service)); err == nil && !stale {
+			fmt.Printf("%s is already current, nothing to do\n", service)
+			return nil
+		}
+
+		if err := m.Build(ctx, service, func(p container.BuildProgress) {
+			if !p.Done {
+				fmt.Println("  " + p.Status)
+			}
+		}); err != nil {
+			return fmt.Errorf("build failed: %w", err)
+		}
+		if err := m.Up(ctx, service); err != nil {
+			return fmt.Errorf("recreate failed: %w", err)
+		}
+		fmt.Printf("%s rebuilt via %s\n", service, m.Engine())
+		return nil
+	},
+}
+
+// engineManager resolves the --engine flag (shared by containersCmd's
+// subcommands) into a container.Manager.
+func engineManager(cmd *cobra.Command) (container.Manager, error) {
+	engine, _ := cmd.Flags().GetString("engine")
+	return container.NewManager(engine)
+}
+
+func init() {
+	containersStatusCmd.Flags().String("engine", "", "container engine to use: docker, podman, nerdctl, or shell (default: auto-detect)")
+	containersRebuildCmd.Flags().String("engine", "", "container engine to use: docker, podman, nerdctl, or shell (default: auto-detect)")
+	containersCmd.AddCommand(containersStatusCmd, containersRebuildCmd)
+	rootCmd.AddCommand(containersCmd)
+}
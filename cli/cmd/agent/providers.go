@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/providers"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/validator"
+	"github.com/spf13/cobra"
+)
+
+var providersCmd = &cobra.Command{
+	Use:   "providers",
+	Short: "Inspect configured AI providers",
+}
+
+var providersListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List every provider registered in the providers registry",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		asJSON, _ := cmd.Flags().GetBool("json")
+		all := providers.All()
+
+		if asJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(all)
+		}
+
+		for _, d := range all {
+			fmt.Printf("%-16s %-24s models=%d\n", d.Name, d.DisplayName, len(d.Models))
+		}
+		return nil
+	},
+}
+
+// providerEnvKey mirrors wizard/config.go's OPENAI_API_KEY/DEEPGRAM_API_KEY
+// env var naming, since that's where a key set up via 'agent setup' lives.
+var providerEnvKey = map[string]string{
+	"openai_realtime": "OPENAI_API_KEY",
+	"deepgram":        "DEEPGRAM_API_KEY",
+	"google_live":     "GOOGLE_API_KEY",
+}
+
+var providersProbeCmd = &cobra.Command{
+	Use:   "probe <provider>",
+	Short: "Probe a provider's API key for auth, model availability, quota, and latency",
+	Long: `Goes beyond a plain key-format/auth check (validator.ValidateAPIKey): for
+openai_realtime, confirms the --model id is in /v1/models (with no --model,
+any listed model counts); for deepgram, surfaces remaining balance via
+/v1/projects/{id}/balances; for google_live, issues a minimal
+GenerateContent call against the --model id (default gemini-2.0-flash-exp)
+instead of just checking key length. The model comes from --model only;
+the agent's config file is not read.
+
+Reads the key from the provider's env var (OPENAI_API_KEY, DEEPGRAM_API_KEY,
+GOOGLE_API_KEY) unless --key is given. Probe results are cached in memory
+for 5 minutes within one process; each run of this command is a new
+process and probes the provider again.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		provider := args[0]
+		envKey, ok := providerEnvKey[provider]
+		if !ok {
+			return fmt.Errorf("no probe available for provider: %s", provider)
+		}
+
+		apiKey, _ := cmd.Flags().GetString("key")
+		if apiKey == "" {
+			apiKey = os.Getenv(envKey)
+		}
+		if apiKey == "" {
+			return fmt.Errorf("no API key: pass --key or set %s", envKey)
+		}
+		model, _ := cmd.Flags().GetString("model")
+
+		report, err := validator.Probe(provider, apiKey, model)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("provider:        %s\n", report.Provider)
+		fmt.Printf("auth_ok:         %v\n", report.AuthOK)
+		fmt.Printf("model_requested: %s\n", report.ModelRequested)
+		fmt.Printf("model_available: %v\n", report.ModelAvailable)
+		if report.Quota != "" {
+			fmt.Printf("quota:           %s\n", report.Quota)
+		}
+		fmt.Printf("latency_ms:      %d\n", report.LatencyMs)
+		if report.Error != "" {
+			fmt.Printf("error:           %s\n", report.Error)
+			return fmt.Errorf("probe reported an issue, see above")
+		}
+		return nil
+	},
+}
+
+func init() {
+	providersProbeCmd.Flags().String("key", "", "API key to probe with (defaults to the provider's env var)")
+	providersProbeCmd.Flags().String("model", "", "model id to check availability for (if empty: openai_realtime accepts any listed model, google_live uses gemini-2.0-flash-exp; deepgram does not check a model id)")
+	providersListCmd.Flags().Bool("json", false, "print the registry as JSON")
+	providersCmd.AddCommand(providersProbeCmd, providersListCmd)
+	rootCmd.AddCommand(providersCmd)
+}
@@ -1,18 +1,82 @@
 package main
 
 import (
+	"fmt"
 	"os"
+	"time"
 
 	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/troubleshoot"
 	"github.com/spf13/cobra"
 )
 
 var (
-	rcaCallID string
-	rcaJSON   bool
-	rcaLLM    bool
+	rcaCallID   string
+	rcaJSON     bool
+	rcaLLM      bool
+	rcaExplain  bool
+	rcaSelfTest string
+
+	rcaFormat    string
+	rcaFailUnder float64
+
+	rcaFollow             bool
+	rcaMinSeverity        string
+	rcaTag                string
+	rcaOnlySymptom        string
+	rcaExcludeHealthy     bool
+	rcaIdleTimeout        time.Duration
+	rcaOutputFile         string
+	rcaOutputFileMaxBytes int64
+
+	rcaLogSource string
+
+	rcaSave string
+
+	rcaTimeseriesOut string
+
+	rcaDiffBaseline  string
+	rcaWriteBaseline string
+
+	rcaBundle string
+
+	rcaCapture                     bool
+	rcaCaptureDir                  string
+	rcaCaptureLossTolerancePercent float64
+	rcaCaptureJitterToleranceMs    float64
+
+	rcaReplayDir string
+
+	rcaWatch          bool
+	rcaWatchWindow    time.Duration
+	rcaWatchInterval  time.Duration
+	rcaWebhook        string
+	rcaWatchThreshold float64
+	rcaWatchDebounce  time.Duration
+	rcaWatchBundleDir string
+	rcaDryRun         bool
 )
 
+// resolveStorePath returns the RCA store path to use: the --save/--store-path
+// flag value if set, else RCA_STORE_PATH, else "" (persistence disabled).
+func resolveStorePath(flagValue string) string {
+	if flagValue != "" {
+		return flagValue
+	}
+	return os.Getenv("RCA_STORE_PATH")
+}
+
+// resolveFormat returns the effective --format value: --format wins if set,
+// else --json for backward compatibility, else "text".
+func resolveFormat(format string, jsonFlag bool) string {
+	if format != "" {
+		return format
+	}
+	if jsonFlag {
+		return "json"
+	}
+	return "text"
+}
+
 var rcaCmd = &cobra.Command{
 	Use:   "rca [call_id]",
 	Short: "Post-call root cause analysis",
@@ -29,6 +93,23 @@ This is the recommended post-call troubleshooting command.`,
 			callID = "last"
 		}
 
+		format := resolveFormat(rcaFormat, rcaJSON)
+
+		if rcaWatch {
+			runner := troubleshoot.NewRunner(callID, "", false, false, false, rcaLLM, false, false, false, verbose)
+			runner.SetLogSource(troubleshoot.NewLogSourceFromEnv(rcaLogSource))
+			runner.SetExplain(rcaExplain)
+			return runner.RunWatch(troubleshoot.WatchOptions{
+				Window:         rcaWatchWindow,
+				Interval:       rcaWatchInterval,
+				WebhookURL:     rcaWebhook,
+				ScoreThreshold: rcaWatchThreshold,
+				DryRun:         rcaDryRun,
+				BundleDir:      rcaWatchBundleDir,
+				Debounce:       rcaWatchDebounce,
+			})
+		}
+
 		runner := troubleshoot.NewRunner(
 			callID,
 			"",    // symptom
@@ -37,11 +118,44 @@ This is the recommended post-call troubleshooting command.`,
 			false, // noLLM (auto gating will skip healthy calls)
 			rcaLLM, // forceLLM
 			false, // list
-			rcaJSON,
+			format == "json",
+			rcaFollow,
 			verbose,
 		)
+		runner.SetOutputFormat(format)
+		runner.SetLogSource(troubleshoot.NewLogSourceFromEnv(rcaLogSource))
+		runner.SetStore(resolveStorePath(rcaSave))
+		runner.SetTimeseriesOut(rcaTimeseriesOut)
+		runner.SetDiffBaseline(rcaDiffBaseline)
+		runner.SetWriteBaseline(rcaWriteBaseline)
+		runner.SetBundlePath(rcaBundle)
+		runner.SetExplain(rcaExplain)
+		runner.SetSelfTest(rcaSelfTest)
+		if rcaFollow {
+			runner.SetFollowOptions(troubleshoot.FollowOptions{
+				MinSeverity:        rcaMinSeverity,
+				Tag:                rcaTag,
+				OnlySymptom:        rcaOnlySymptom,
+				ExcludeHealthy:     rcaExcludeHealthy,
+				IdleTimeout:        rcaIdleTimeout,
+				OutputFile:         rcaOutputFile,
+				OutputFileMaxBytes: rcaOutputFileMaxBytes,
+
+				Capture:                     rcaCapture,
+				CaptureDir:                  rcaCaptureDir,
+				CaptureLossTolerancePercent: rcaCaptureLossTolerancePercent,
+				CaptureJitterToleranceMs:    rcaCaptureJitterToleranceMs,
+
+				ReplayDir: rcaReplayDir,
+			})
+		}
 		err := runner.Run()
-		if rcaJSON && err != nil {
+		if err == nil && !rcaFollow {
+			if rep := runner.LastReport(); rep != nil && rep.Metrics != nil && rcaFailUnder > 0 && rep.QualityScore < rcaFailUnder {
+				err = fmt.Errorf("RCA quality score %.0f is below --fail-under threshold %.0f", rep.QualityScore, rcaFailUnder)
+			}
+		}
+		if format != "text" && err != nil {
 			os.Exit(1)
 		}
 		return err
@@ -51,6 +165,40 @@ This is the recommended post-call troubleshooting command.`,
 func init() {
 	rcaCmd.Flags().StringVar(&rcaCallID, "call", "", "analyze specific call ID (default: last)")
 	rcaCmd.Flags().BoolVar(&rcaLLM, "llm", false, "force LLM analysis (even for healthy calls)")
-	rcaCmd.Flags().BoolVar(&rcaJSON, "json", false, "output as JSON (JSON only)")
+	rcaCmd.Flags().BoolVar(&rcaExplain, "explain", false, "anonymize findings (SIP URIs, phone numbers, IPs, API keys, tool-call args) before sending them to the LLM, and cache responses by sanitized input")
+	rcaCmd.Flags().StringVar(&rcaSelfTest, "self-test", "", "quick|full: actively originate a loopback call and feed objective transport/STT metrics into the analysis, instead of only reading logs (requires a reachable Asterisk ARI endpoint)")
+	rcaCmd.Flags().BoolVar(&rcaJSON, "json", false, "output as JSON (shorthand for --format=json)")
+	rcaCmd.Flags().StringVar(&rcaFormat, "format", "", "output format: text (default), json, or junit")
+	rcaCmd.Flags().Float64Var(&rcaFailUnder, "fail-under", 70.0, "exit non-zero if the call quality score is below this (0 disables)")
+	rcaCmd.Flags().StringVar(&rcaLogSource, "log-source", "", "where to read ai_engine logs from: docker (default), file, journald, kubectl (env: RCA_LOG_SOURCE)")
+	rcaCmd.Flags().StringVar(&rcaSave, "save", "", "persist this report to a local RCA history SQLite database at this path (env: RCA_STORE_PATH)")
+	rcaCmd.Flags().StringVar(&rcaTimeseriesOut, "timeseries-out", "", "dump one CSV row per streaming segment (drift/underflow/gate-closure trend) to this path")
+	rcaCmd.Flags().StringVar(&rcaDiffBaseline, "diff-baseline", "", "compare this call's metrics against baselines/<name>.json (or a built-in default) and show PASS/FAIL per field")
+	rcaCmd.Flags().StringVar(&rcaWriteBaseline, "write-baseline", "", "snapshot this call's metrics into a new golden baseline JSON file at this path")
+	rcaCmd.Flags().StringVar(&rcaBundle, "bundle", "", "export a zip incident bundle (raw log, report JSON/Markdown, redacted config) to this path for support tickets")
+
+	rcaCmd.Flags().BoolVar(&rcaFollow, "follow", false, "stream ai_engine logs live and emit one report per call as it ends")
+	rcaCmd.Flags().StringVar(&rcaMinSeverity, "min-severity", "info", "--follow: minimum severity to echo live (error/warn/info/debug)")
+	rcaCmd.Flags().StringVar(&rcaTag, "tag", "", "--follow: only report calls whose logs contain this substring")
+	rcaCmd.Flags().StringVar(&rcaOnlySymptom, "only-symptom", "", "--follow: only report calls where this symptom's checker finds a root cause")
+	rcaCmd.Flags().BoolVar(&rcaExcludeHealthy, "exclude-healthy", false, "--follow: don't report calls that look healthy")
+	rcaCmd.Flags().DurationVar(&rcaIdleTimeout, "idle-timeout", 30*time.Second, "--follow: finalize a call after this long with no new log lines")
+	rcaCmd.Flags().StringVar(&rcaOutputFile, "output-file", "", "--follow: append NDJSON reports to this file instead of printing a human panel")
+	rcaCmd.Flags().Int64Var(&rcaOutputFileMaxBytes, "output-file-max-bytes", 50*1024*1024, "--follow: rotate --output-file once it exceeds this size")
+	rcaCmd.Flags().BoolVar(&rcaCapture, "capture", false, "--follow: record RTP/AudioSocket traffic with tcpdump and cross-check ground-truth packet metrics against the logs")
+	rcaCmd.Flags().StringVar(&rcaCaptureDir, "capture-dir", "", "--follow --capture: directory to save .pcap files in (default: OS temp dir)")
+	rcaCmd.Flags().Float64Var(&rcaCaptureLossTolerancePercent, "capture-loss-tolerance-pct", 1.0, "--follow --capture: warn if measured RTP loss exceeds this percent with no logged underflows")
+	rcaCmd.Flags().Float64Var(&rcaCaptureJitterToleranceMs, "capture-jitter-tolerance-ms", 20.0, "--follow --capture: warn if measured RTP jitter (ms) exceeds this with no logged underflows/gate closures")
+	rcaCmd.Flags().StringVar(&rcaReplayDir, "replay-dir", "", "--follow --capture: write an HLS-style replay manifest (.m3u8 + .wav segments) for each call's captured audio into this directory")
+
+	rcaCmd.Flags().BoolVar(&rcaWatch, "watch", false, "continuously re-analyze active calls on a timer and POST webhook alerts, instead of waiting for each call to end")
+	rcaCmd.Flags().DurationVar(&rcaWatchWindow, "window", 5*time.Minute, "--watch: how much trailing log history to re-analyze per call")
+	rcaCmd.Flags().DurationVar(&rcaWatchInterval, "interval", 30*time.Second, "--watch: how often to re-run the analysis pipeline over the window")
+	rcaCmd.Flags().StringVar(&rcaWebhook, "webhook", "", "--watch: URL to POST alert JSON to (Slack-compatible or generic)")
+	rcaCmd.Flags().Float64Var(&rcaWatchThreshold, "watch-threshold", 70.0, "--watch: alert when the rolling quality score drops below this")
+	rcaCmd.Flags().DurationVar(&rcaWatchDebounce, "watch-debounce", 5*time.Minute, "--watch: minimum time between two alerts for the same call")
+	rcaCmd.Flags().StringVar(&rcaWatchBundleDir, "watch-bundle-dir", "", "--watch: directory to write an incident bundle alongside each alert (default: OS temp dir)")
+	rcaCmd.Flags().BoolVar(&rcaDryRun, "dry-run", false, "--watch: print what would be POSTed to --webhook instead of sending it")
+
 	rootCmd.AddCommand(rcaCmd)
 }
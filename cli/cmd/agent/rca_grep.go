@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/troubleshoot"
+	"github.com/spf13/cobra"
+)
+
+var rcaGrepCmd = &cobra.Command{
+	Use:   "grep <path> <logfile>",
+	Short: "Extract structured facts from a log file with a gjson-style path query",
+	Long: `Evaluates a path expression against every line of logfile (JSON-per-line
+or console/structlog, same as the rest of the rca tooling) and prints each
+match.
+
+Path syntax: dotted keys, array indices (written implicitly via the
+flattened field, e.g. metrics[0].value), and a "#(field==value)" filter
+clause, optionally joined with && / ||. Examples:
+
+  #(event=="tool_call" && level=="error").call_id
+  metrics.#(name=="latency_ms").value
+
+This lets you pull structured facts out of mixed JSON/console Asterisk
+logs without shelling out to jq.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, logfile := args[0], args[1]
+
+		f, err := os.Open(logfile)
+		if err != nil {
+			return fmt.Errorf("open %s: %w", logfile, err)
+		}
+		defer f.Close()
+
+		results, err := troubleshoot.QueryReader(f, path)
+		if err != nil {
+			return err
+		}
+		if len(results) == 0 {
+			fmt.Println("no matches")
+			return nil
+		}
+		for _, r := range results {
+			fmt.Printf("%d: %s\n", r.Line, r.Value)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rcaCmd.AddCommand(rcaGrepCmd)
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/troubleshoot"
+	"github.com/spf13/cobra"
+)
+
+var (
+	rcaHistoryStorePath string
+	rcaHistoryFilter    string
+	rcaHistoryLimit     int
+	rcaHistoryJSON      bool
+)
+
+var rcaHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Search saved RCA reports (requires --save on prior `agent rca` runs)",
+	Long: `Search the local RCA history database for calls matching a small filter DSL, e.g.:
+
+  agent rca history --filter 'provider=deepgram transport=externalmedia symptom=barge_in drift_pct>5 since=24h grep="underflow"'
+
+Only calls previously analyzed with --save (or RCA_STORE_PATH set) are in the database.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		storePath := resolveStorePath(rcaHistoryStorePath)
+		if storePath == "" {
+			return fmt.Errorf("no RCA store configured; pass --store-path or set RCA_STORE_PATH")
+		}
+		runner := troubleshoot.NewRunner("", "", false, false, false, false, false, rcaHistoryJSON, false, verbose)
+		runner.SetStore(storePath)
+		return runner.RunHistory(troubleshoot.HistoryOptions{
+			Filter: rcaHistoryFilter,
+			Limit:  rcaHistoryLimit,
+		})
+	},
+}
+
+func init() {
+	rcaHistoryCmd.Flags().StringVar(&rcaHistoryStorePath, "store-path", "", "RCA history database path (env: RCA_STORE_PATH)")
+	rcaHistoryCmd.Flags().StringVar(&rcaHistoryFilter, "filter", "", `filter DSL, e.g. provider=deepgram transport=externalmedia drift_pct>5 since=24h grep="underflow"`)
+	rcaHistoryCmd.Flags().IntVar(&rcaHistoryLimit, "limit", 50, "maximum number of matching calls to print")
+	rcaHistoryCmd.Flags().BoolVar(&rcaHistoryJSON, "json", false, "output as JSON (JSON only)")
+	rcaCmd.AddCommand(rcaHistoryCmd)
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/troubleshoot"
+	"github.com/spf13/cobra"
+)
+
+var (
+	rcaQueryStorePath string
+	rcaQueryJSON      bool
+)
+
+var rcaQueryCmd = &cobra.Command{
+	Use:   "query <call_id>",
+	Short: "Re-render a saved RCA report by call ID, without needing the original logs",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		storePath := resolveStorePath(rcaQueryStorePath)
+		if storePath == "" {
+			return fmt.Errorf("no RCA store configured; pass --store-path or set RCA_STORE_PATH")
+		}
+		runner := troubleshoot.NewRunner("", "", false, false, false, false, false, rcaQueryJSON, false, verbose)
+		runner.SetStore(storePath)
+		return runner.RunQuery(args[0])
+	},
+}
+
+func init() {
+	rcaQueryCmd.Flags().StringVar(&rcaQueryStorePath, "store-path", "", "RCA history database path (env: RCA_STORE_PATH)")
+	rcaQueryCmd.Flags().BoolVar(&rcaQueryJSON, "json", false, "output as JSON (JSON only)")
+	rcaCmd.AddCommand(rcaQueryCmd)
+}
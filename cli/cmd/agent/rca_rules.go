@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/troubleshoot/rules"
+	"github.com/spf13/cobra"
+)
+
+var (
+	successColor = color.New(color.FgGreen)
+	errorColor   = color.New(color.FgRed)
+)
+
+var rcaRulesCmd = &cobra.Command{
+	Use:   "rules",
+	Short: "Inspect the data-driven symptom rule engine",
+	Long: fmt.Sprintf(`List, validate, or explain the rules that drive symptom analysis
+alongside the built-in checks.
+
+Default rules ship embedded in the binary; site-specific rules can be
+dropped into %s/ (or TROUBLESHOOT_RULES_DIR) and are
+merged on top, overriding a default rule with the same id.`, rules.SiteRulesDir),
+}
+
+var rcaRulesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the merged ruleset (default + site overrides)",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rs, err := rules.LoadMerged()
+		if err != nil {
+			return err
+		}
+		for _, r := range rs {
+			fmt.Printf("%-24s %-12s %s\n", r.ID, r.Symptom, r.When)
+		}
+		return nil
+	},
+}
+
+var rcaRulesValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Validate the merged ruleset's schema, when-expressions, and templates",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rs, err := rules.LoadMerged()
+		if err != nil {
+			return err
+		}
+		errs := rules.Validate(rs)
+		if len(errs) == 0 {
+			successColor.Printf("✅ %d rules valid\n", len(rs))
+			return nil
+		}
+		for _, e := range errs {
+			errorColor.Printf("❌ %v\n", e)
+		}
+		return fmt.Errorf("%d rule validation error(s)", len(errs))
+	},
+}
+
+var rcaRulesExplainCmd = &cobra.Command{
+	Use:   "explain <id>",
+	Short: "Print one rule's definition in full",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rs, err := rules.LoadMerged()
+		if err != nil {
+			return err
+		}
+		for _, r := range rs {
+			if r.ID != args[0] {
+				continue
+			}
+			fmt.Printf("id:         %s\n", r.ID)
+			fmt.Printf("symptom:    %s\n", r.Symptom)
+			fmt.Printf("severity:   %s\n", r.Severity)
+			fmt.Printf("when:       %s\n", r.When)
+			fmt.Printf("finding:    %s\n", r.Finding)
+			fmt.Printf("root_cause: %s\n", r.RootCause)
+			fmt.Printf("actions:\n")
+			for _, a := range r.Actions {
+				fmt.Printf("  - %s\n", a)
+			}
+			return nil
+		}
+		return fmt.Errorf("no rule with id %q", args[0])
+	},
+}
+
+func init() {
+	rcaRulesCmd.AddCommand(rcaRulesListCmd, rcaRulesValidateCmd, rcaRulesExplainCmd)
+	rcaCmd.AddCommand(rcaRulesCmd)
+}
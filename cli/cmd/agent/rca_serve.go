@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/troubleshoot"
+	"github.com/spf13/cobra"
+)
+
+var (
+	rcaServeGRPCAddr     string
+	rcaServeStorePath    string
+	rcaServeCaptureDir   string
+	rcaServeProfilesHTTP string
+	rcaServeProfilesFile string
+)
+
+var rcaServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve RCA results and captured call audio over gRPC",
+	Long: `Expose RCA history and --capture pcaps to external consumers
+(dashboards, LLM post-mortems) over the RCADataService gRPC API defined in
+proto/rca_data.proto, instead of requiring them to parse agent rca's own
+text/JSON output.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if rcaServeProfilesFile != "" {
+			registry, err := troubleshoot.LoadFormatProfiles(rcaServeProfilesFile)
+			if err != nil {
+				return err
+			}
+			troubleshoot.SetFormatProfiles(registry)
+		}
+
+		storePath := resolveStorePath(rcaServeStorePath)
+		if storePath == "" {
+			return fmt.Errorf("--store-path (or RCA_STORE_PATH) is required to serve GetRCAResult")
+		}
+		srv, err := troubleshoot.NewRCADataServer(storePath, rcaServeCaptureDir)
+		if err != nil {
+			return err
+		}
+		defer srv.Close()
+
+		if rcaServeProfilesHTTP != "" {
+			mux := http.NewServeMux()
+			mux.HandleFunc("/rca/profiles", troubleshoot.ProfilesHTTPHandler)
+			go func() {
+				fmt.Fprintf(os.Stderr, "GET /rca/profiles listening on %s\n", rcaServeProfilesHTTP)
+				if err := http.ListenAndServe(rcaServeProfilesHTTP, mux); err != nil {
+					fmt.Fprintf(os.Stderr, "profiles HTTP server stopped: %v\n", err)
+				}
+			}()
+		}
+
+		fmt.Fprintf(os.Stderr, "RCADataService listening on %s\n", rcaServeGRPCAddr)
+		return srv.Serve(rcaServeGRPCAddr)
+	},
+}
+
+func init() {
+	rcaServeCmd.Flags().StringVar(&rcaServeGRPCAddr, "grpc-addr", ":50051", "address to listen on")
+	rcaServeCmd.Flags().StringVar(&rcaServeStorePath, "store-path", "", "RCA history SQLite database to serve GetRCAResult from (env: RCA_STORE_PATH)")
+	rcaServeCmd.Flags().StringVar(&rcaServeCaptureDir, "capture-dir", "", "directory of --capture pcaps to serve GetAudioSegment/GetPeaks from")
+	rcaServeCmd.Flags().StringVar(&rcaServeProfilesFile, "profiles-file", "", "profiles.yaml declaring per-pipeline FormatProfile golden baselines")
+	rcaServeCmd.Flags().StringVar(&rcaServeProfilesHTTP, "profiles-http-addr", "", "if set, also serve GET /rca/profiles on this address")
+	rcaCmd.AddCommand(rcaServeCmd)
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/troubleshoot"
+	"github.com/spf13/cobra"
+)
+
+var (
+	rcaStatsSince  string
+	rcaStatsLimit  int
+	rcaStatsGrep   string
+	rcaStatsJSON   bool
+	rcaStatsLogSrc string
+)
+
+var rcaStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Cross-call RCA analytics (failure distributions, latency percentiles)",
+	Long: `Run RCA across the last N calls (or a time window) instead of a single call,
+and print aggregate failure-reason distributions and percentile histograms.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		runner := troubleshoot.NewRunner(
+			"",    // callID (unused in stats mode)
+			"",    // symptom
+			false, // interactive
+			false, // collectOnly
+			false, // noLLM
+			false, // forceLLM
+			false, // list
+			rcaStatsJSON,
+			false, // follow
+			verbose,
+		)
+		runner.SetLogSource(troubleshoot.NewLogSourceFromEnv(rcaStatsLogSrc))
+		return runner.RunStats(troubleshoot.StatsOptions{
+			Since: rcaStatsSince,
+			Limit: rcaStatsLimit,
+			Grep:  rcaStatsGrep,
+		})
+	},
+}
+
+func init() {
+	rcaStatsCmd.Flags().StringVar(&rcaStatsSince, "since", "24h", "time window to aggregate over")
+	rcaStatsCmd.Flags().IntVar(&rcaStatsLimit, "limit", 200, "maximum number of calls to aggregate")
+	rcaStatsCmd.Flags().StringVar(&rcaStatsGrep, "grep", "", "only include calls whose logs match this substring or regex")
+	rcaStatsCmd.Flags().BoolVar(&rcaStatsJSON, "json", false, "output as JSON (JSON only)")
+	rcaStatsCmd.Flags().StringVar(&rcaStatsLogSrc, "log-source", "", "where to read ai_engine logs from: docker (default), file, journald, kubectl (env: RCA_LOG_SOURCE)")
+	rcaCmd.AddCommand(rcaStatsCmd)
+}
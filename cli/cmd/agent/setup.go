@@ -20,6 +20,8 @@ Notes:
   - Prints the expected Stasis app name and dialplan snippet`,
 		version),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		wizard.Engine, _ = cmd.Flags().GetString("engine")
+
 		w, err := wizard.NewWizard()
 		if err != nil {
 			return fmt.Errorf("failed to initialize wizard: %w", err)
@@ -38,5 +40,6 @@ Notes:
 }
 
 func init() {
+	setupCmd.Flags().String("engine", "", "container engine to use for rebuilds: docker, podman, nerdctl, or shell (default: auto-detect)")
 	rootCmd.AddCommand(setupCmd)
 }
@@ -0,0 +1,407 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Fix is one discrete, applied repair: what changed, where (by JSON
+// pointer), and the before/after values, so --dry-run can print a readable
+// diff and --interactive can ask about each one individually.
+type Fix struct {
+	Description string
+	Pointer     string
+	Before      string
+	After       string
+}
+
+// FixOptions controls how AutoFixWithOptions applies fixes.
+type FixOptions struct {
+	// DryRun, when true, computes fixes and a diff but doesn't write the file.
+	DryRun bool
+	// Confirm, when non-nil, is called once per candidate fix; returning
+	// false skips it. Interactive callers (a CLI prompt) pass a function
+	// here; nil means "apply every fix" (the old AutoFix behavior).
+	Confirm func(Fix) bool
+	// Backup, when true, writes a timestamped .bak of the original file
+	// before overwriting it. Ignored when DryRun is true.
+	Backup bool
+}
+
+// deprecatedModels maps a provider to old model id -> current replacement.
+// Both sides must be checked against the registry's/schema's notion of
+// "valid" as those evolve; this table only needs to grow when a provider
+// retires a model id.
+var deprecatedModels = map[string]map[string]string{
+	"openai_realtime": {
+		"gpt-4o-realtime-preview-2024-10-01": "gpt-4o-realtime-preview",
+	},
+	"google_live": {
+		"models/gemini-2.0-flash-exp": "models/gemini-2.0-flash-live-001",
+	},
+}
+
+// AutoFix attempts to fix common issues, applying every available fix and
+// writing the file in place. It's AutoFixWithOptions with the defaults that
+// match the old placeholder's implied contract (no dry-run, no prompting).
+func (v *Validator) AutoFix(result *ValidationResult) (int, error) {
+	fixes, _, err := v.AutoFixWithOptions(FixOptions{})
+	return len(fixes), err
+}
+
+// AutoFixWithOptions loads the config, applies every fix rule that matches,
+// and - unless opts.DryRun - writes the result back, preserving comments and
+// key order via yaml.Node round-tripping. It returns every Fix that was
+// applied (or, in dry-run mode, would have been) plus a unified diff of the
+// whole file so --dry-run can show exactly what would change.
+func (v *Validator) AutoFixWithOptions(opts FixOptions) (applied []Fix, diff string, err error) {
+	data, err := os.ReadFile(v.configPath)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, "", fmt.Errorf("invalid YAML syntax: %w", err)
+	}
+	if len(root.Content) == 0 {
+		return nil, "", nil
+	}
+	docRoot := root.Content[0]
+
+	applied = collectFixes(docRoot, opts.Confirm)
+	if len(applied) == 0 {
+		return nil, "", nil
+	}
+
+	out, err := yaml.Marshal(&root)
+	if err != nil {
+		return applied, "", fmt.Errorf("failed to re-encode config: %w", err)
+	}
+	diff = UnifiedDiff(v.configPath, v.configPath+" (fixed)", string(data), string(out))
+
+	if opts.DryRun {
+		return applied, diff, nil
+	}
+
+	if opts.Backup {
+		bakPath := fmt.Sprintf("%s.%s.bak", v.configPath, time.Now().UTC().Format("20060102T150405Z"))
+		if err := os.WriteFile(bakPath, data, 0o644); err != nil {
+			return applied, diff, fmt.Errorf("failed to write backup %s: %w", bakPath, err)
+		}
+	}
+
+	if err := os.WriteFile(v.configPath, out, 0o644); err != nil {
+		return applied, diff, fmt.Errorf("failed to write config file: %w", err)
+	}
+	return applied, diff, nil
+}
+
+// collectFixes mutates docRoot in place for every rule that matches and
+// (when confirm is non-nil) is confirmed, returning one Fix per applied
+// change. Rules run in a fixed order so a legacy-key rename happens before
+// the sample-rate/model rules that read the renamed key. confirm is checked
+// before each mutation, never after, so a rejected fix never touches the
+// tree.
+func collectFixes(docRoot *yaml.Node, confirm func(Fix) bool) []Fix {
+	var fixes []Fix
+	fixes = append(fixes, normalizeLegacyKeys(docRoot, confirm)...)
+	fixes = append(fixes, replaceDeprecatedModels(docRoot, confirm)...)
+	fixes = append(fixes, alignSampleRates(docRoot, confirm)...)
+	fixes = append(fixes, clampBargeInProtection(docRoot, confirm)...)
+	fixes = append(fixes, addContextsScaffold(docRoot, confirm)...)
+	return fixes
+}
+
+// ok reports whether a candidate fix should be applied: yes, unless confirm
+// is set and rejects it.
+func ok(confirm func(Fix) bool, fix Fix) bool {
+	return confirm == nil || confirm(fix)
+}
+
+func normalizeLegacyKeys(docRoot *yaml.Node, confirm func(Fix) bool) []Fix {
+	var fixes []Fix
+	providers := mapGet(docRoot, "providers")
+	if providers == nil || providers.Kind != yaml.MappingNode {
+		return fixes
+	}
+	forEachMapEntry(providers, func(name string, provider *yaml.Node) {
+		if provider.Kind != yaml.MappingNode {
+			return
+		}
+		if mapGet(provider, "input_sample_rate_hz") != nil && mapGet(provider, "provider_input_sample_rate_hz") == nil {
+			fix := Fix{
+				Description: fmt.Sprintf("renamed legacy key input_sample_rate_hz to provider_input_sample_rate_hz for provider %q", name),
+				Pointer:     "/providers/" + name + "/provider_input_sample_rate_hz",
+			}
+			if ok(confirm, fix) && renameKey(provider, "input_sample_rate_hz", "provider_input_sample_rate_hz") {
+				fixes = append(fixes, fix)
+			}
+		}
+		if mapGet(provider, "output_sample_rate_hz") != nil && mapGet(provider, "provider_output_sample_rate_hz") == nil {
+			fix := Fix{
+				Description: fmt.Sprintf("renamed legacy key output_sample_rate_hz to provider_output_sample_rate_hz for provider %q", name),
+				Pointer:     "/providers/" + name + "/provider_output_sample_rate_hz",
+			}
+			if ok(confirm, fix) && renameKey(provider, "output_sample_rate_hz", "provider_output_sample_rate_hz") {
+				fixes = append(fixes, fix)
+			}
+		}
+		if name == "google_live" && mapGet(provider, "model") != nil && mapGet(provider, "llm_model") == nil {
+			fix := Fix{
+				Description: "renamed legacy key model to llm_model for provider \"google_live\"",
+				Pointer:     "/providers/google_live/llm_model",
+			}
+			if ok(confirm, fix) && renameKey(provider, "model", "llm_model") {
+				fixes = append(fixes, fix)
+			}
+		}
+	})
+	return fixes
+}
+
+func replaceDeprecatedModels(docRoot *yaml.Node, confirm func(Fix) bool) []Fix {
+	var fixes []Fix
+	providers := mapGet(docRoot, "providers")
+	if providers == nil || providers.Kind != yaml.MappingNode {
+		return fixes
+	}
+	forEachMapEntry(providers, func(name string, provider *yaml.Node) {
+		replacements, isKnown := deprecatedModels[name]
+		if !isKnown || provider.Kind != yaml.MappingNode {
+			return
+		}
+		for _, key := range []string{"model", "llm_model"} {
+			valueNode := mapGet(provider, key)
+			if valueNode == nil || valueNode.Kind != yaml.ScalarNode {
+				continue
+			}
+			replacement, hasReplacement := replacements[valueNode.Value]
+			if !hasReplacement || replacement == valueNode.Value {
+				continue
+			}
+			fix := Fix{
+				Description: fmt.Sprintf("updated deprecated model id for provider %q", name),
+				Pointer:     "/providers/" + name + "/" + key,
+				Before:      valueNode.Value,
+				After:       replacement,
+			}
+			if ok(confirm, fix) {
+				valueNode.Value = replacement
+				fixes = append(fixes, fix)
+			}
+		}
+	})
+	return fixes
+}
+
+func alignSampleRates(docRoot *yaml.Node, confirm func(Fix) bool) []Fix {
+	var fixes []Fix
+	providers := mapGet(docRoot, "providers")
+	if providers == nil || providers.Kind != yaml.MappingNode {
+		return fixes
+	}
+	forEachMapEntry(providers, func(name string, provider *yaml.Node) {
+		if provider.Kind != yaml.MappingNode {
+			return
+		}
+		inputNode := mapGet(provider, "provider_input_sample_rate_hz")
+		outputNode := mapGet(provider, "provider_output_sample_rate_hz")
+		if inputNode == nil || outputNode == nil || inputNode.Value == outputNode.Value {
+			return
+		}
+		fix := Fix{
+			Description: fmt.Sprintf("aligned provider %q output sample rate to its input rate", name),
+			Pointer:     "/providers/" + name + "/provider_output_sample_rate_hz",
+			Before:      outputNode.Value,
+			After:       inputNode.Value,
+		}
+		if ok(confirm, fix) {
+			outputNode.Value = inputNode.Value
+			fixes = append(fixes, fix)
+		}
+	})
+	return fixes
+}
+
+func clampBargeInProtection(docRoot *yaml.Node, confirm func(Fix) bool) []Fix {
+	bargeIn := mapGet(docRoot, "barge_in")
+	if bargeIn == nil || bargeIn.Kind != yaml.MappingNode {
+		return nil
+	}
+	protectionNode := mapGet(bargeIn, "post_tts_end_protection_ms")
+	if protectionNode == nil || protectionNode.Kind != yaml.ScalarNode {
+		return nil
+	}
+	ms, err := strconv.Atoi(protectionNode.Value)
+	if err != nil {
+		return nil
+	}
+	clamped := ms
+	if clamped < 100 {
+		clamped = 100
+	} else if clamped > 500 {
+		clamped = 500
+	}
+	if clamped == ms {
+		return nil
+	}
+	fix := Fix{
+		Description: "clamped post_tts_end_protection_ms into the recommended 100-500 range",
+		Pointer:     "/barge_in/post_tts_end_protection_ms",
+		Before:      protectionNode.Value,
+		After:       strconv.Itoa(clamped),
+	}
+	if !ok(confirm, fix) {
+		return nil
+	}
+	protectionNode.Value = fix.After
+	return []Fix{fix}
+}
+
+func addContextsScaffold(docRoot *yaml.Node, confirm func(Fix) bool) []Fix {
+	if mapGet(docRoot, "contexts") != nil {
+		return nil
+	}
+	fix := Fix{
+		Description: "added an empty contexts: scaffold (engine will otherwise use the default context only)",
+		Pointer:     "/contexts",
+		After:       "{}",
+	}
+	if !ok(confirm, fix) {
+		return nil
+	}
+	keyNode := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "contexts"}
+	valueNode := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	docRoot.Content = append(docRoot.Content, keyNode, valueNode)
+	return []Fix{fix}
+}
+
+// ---- yaml.Node helpers ----
+
+// mapGet returns the value node for key in a mapping node, or nil.
+func mapGet(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// forEachMapEntry calls fn(key, valueNode) for every entry in a mapping
+// node, in document order.
+func forEachMapEntry(node *yaml.Node, fn func(key string, value *yaml.Node)) {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		fn(node.Content[i].Value, node.Content[i+1])
+	}
+}
+
+// renameKey moves oldKey's value to newKey in place (preserving its
+// position), unless newKey is already present - in which case oldKey's
+// value is left alone rather than silently overwriting an explicit setting.
+// Returns whether a rename happened.
+func renameKey(node *yaml.Node, oldKey, newKey string) bool {
+	if mapGet(node, newKey) != nil {
+		return false
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == oldKey {
+			node.Content[i].Value = newKey
+			return true
+		}
+	}
+	return false
+}
+
+// UnifiedDiff renders a minimal unified diff between two whole-file texts,
+// for --dry-run to show operators exactly what AutoFix would change before
+// they commit to it.
+func UnifiedDiff(fromLabel, toLabel, before, after string) string {
+	beforeLines := strings.Split(before, "\n")
+	afterLines := strings.Split(after, "\n")
+	ops := diffLines(beforeLines, afterLines)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", fromLabel, toLabel)
+	for _, op := range ops {
+		switch op.kind {
+		case diffEqual:
+			fmt.Fprintf(&b, "  %s\n", op.line)
+		case diffRemove:
+			fmt.Fprintf(&b, "- %s\n", op.line)
+		case diffAdd:
+			fmt.Fprintf(&b, "+ %s\n", op.line)
+		}
+	}
+	return b.String()
+}
+
+type diffOpKind int
+
+const (
+	diffEqual diffOpKind = iota
+	diffRemove
+	diffAdd
+)
+
+type diffOp struct {
+	kind diffOpKind
+	line string
+}
+
+// diffLines computes a simple LCS-based line diff. Config files are small
+// (tens of lines), so the O(n*m) table here is not worth optimizing away.
+func diffLines(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var ops []diffOp
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{diffEqual, a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{diffRemove, a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{diffAdd, b[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		ops = append(ops, diffOp{diffRemove, a[i]})
+	}
+	for ; j < m; j++ {
+		ops = append(ops, diffOp{diffAdd, b[j]})
+	}
+	return ops
+}
@@ -0,0 +1,280 @@
+package config
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed schema/*.json
+var schemaFS embed.FS
+
+// DefaultSchemaVersion is used when a config has no $schema header.
+const DefaultSchemaVersion = "v1"
+
+// schemaVersions maps a "$schema" pin to the embedded schema file that
+// validates it. New revisions get their own file here so a config that
+// hasn't been migrated yet (an older $schema value) still validates
+// cleanly against the rules it was actually written against.
+var schemaVersions = map[string]string{
+	"v1":                              "schema/v1.json",
+	"asterisk-ai-voice-agent/config/v1": "schema/v1.json",
+}
+
+// JSONSchema is the subset of JSON Schema this package understands:
+// type, required, properties, additionalProperties, and enum. It's
+// intentionally not a general-purpose implementation - config/ai-agent.yaml
+// doesn't need $ref, oneOf, or pattern matching, and a hand-rolled subset is
+// easier to keep correct without a compiler in the loop than pulling in a
+// full JSON Schema library.
+type JSONSchema struct {
+	Type                 string                 `json:"type,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	AdditionalProperties *JSONSchema            `json:"additionalProperties,omitempty"`
+	Enum                 []string               `json:"enum,omitempty"`
+}
+
+// LoadSchema returns the schema registered for the given $schema pin,
+// falling back to DefaultSchemaVersion when pin is empty.
+func LoadSchema(pin string) (*JSONSchema, error) {
+	if pin == "" {
+		pin = DefaultSchemaVersion
+	}
+	path, ok := schemaVersions[pin]
+	if !ok {
+		return nil, fmt.Errorf("unknown config schema version: %s", pin)
+	}
+	data, err := schemaFS.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("embedded schema %s missing: %w", path, err)
+	}
+	var s JSONSchema
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("embedded schema %s invalid: %w", path, err)
+	}
+	return &s, nil
+}
+
+// RawSchema returns the embedded schema document text for the given pin, for
+// `agent config schema` to emit verbatim (editors want the real JSON, not a
+// round-tripped re-encoding of our internal struct).
+func RawSchema(pin string) ([]byte, error) {
+	if pin == "" {
+		pin = DefaultSchemaVersion
+	}
+	path, ok := schemaVersions[pin]
+	if !ok {
+		return nil, fmt.Errorf("unknown config schema version: %s", pin)
+	}
+	return schemaFS.ReadFile(path)
+}
+
+// Issue is a single structured validation finding: where in the document it
+// occurred (JSON pointer plus the YAML source line/column), what went wrong,
+// and - where we can infer one - what the operator probably meant instead.
+type Issue struct {
+	Code       string
+	Severity   string // "error", "warning"
+	Pointer    string // e.g. "/providers/openai_realtime/model"
+	Line       int
+	Column     int
+	Message    string
+	Suggestion string
+}
+
+func (i Issue) String() string {
+	loc := i.Pointer
+	if i.Line > 0 {
+		loc = fmt.Sprintf("%s (line %d, col %d)", loc, i.Line, i.Column)
+	}
+	msg := fmt.Sprintf("[%s] %s: %s", i.Code, loc, i.Message)
+	if i.Suggestion != "" {
+		msg += fmt.Sprintf(" (did you mean %q?)", i.Suggestion)
+	}
+	return msg
+}
+
+// validateAgainstSchema walks node (expected to be a YAML mapping node, the
+// document root) against schema, appending one Issue per violation. pointer
+// is the JSON pointer of node itself ("" at the root).
+func validateAgainstSchema(schema *JSONSchema, node *yaml.Node, pointer string) []Issue {
+	if schema == nil || node == nil {
+		return nil
+	}
+	node = resolveAlias(node)
+
+	var issues []Issue
+	if schema.Type != "" && !nodeMatchesType(node, schema.Type) {
+		issues = append(issues, Issue{
+			Code:     "CFG-TYPE",
+			Severity: "error",
+			Pointer:  pointerOrRoot(pointer),
+			Line:     node.Line,
+			Column:   node.Column,
+			Message:  fmt.Sprintf("expected type %s, got %s", schema.Type, yamlKind(node)),
+		})
+		return issues
+	}
+
+	if len(schema.Enum) > 0 && node.Kind == yaml.ScalarNode {
+		if !containsString(schema.Enum, node.Value) {
+			issues = append(issues, Issue{
+				Code:       "CFG-ENUM",
+				Severity:   "error",
+				Pointer:    pointerOrRoot(pointer),
+				Line:       node.Line,
+				Column:     node.Column,
+				Message:    fmt.Sprintf("%q is not one of %v", node.Value, schema.Enum),
+				Suggestion: nearestMatch(node.Value, schema.Enum),
+			})
+		}
+	}
+
+	if node.Kind != yaml.MappingNode {
+		return issues
+	}
+
+	present := map[string]*yaml.Node{}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		present[node.Content[i].Value] = node.Content[i+1]
+	}
+
+	for _, req := range schema.Required {
+		if _, ok := present[req]; !ok {
+			issues = append(issues, Issue{
+				Code:     "CFG-REQUIRED",
+				Severity: "error",
+				Pointer:  pointer + "/" + req,
+				Line:     node.Line,
+				Column:   node.Column,
+				Message:  fmt.Sprintf("missing required field %q", req),
+			})
+		}
+	}
+
+	for key, child := range present {
+		childPointer := pointer + "/" + key
+		if sub, ok := schema.Properties[key]; ok {
+			issues = append(issues, validateAgainstSchema(sub, child, childPointer)...)
+		} else if schema.AdditionalProperties != nil {
+			issues = append(issues, validateAgainstSchema(schema.AdditionalProperties, child, childPointer)...)
+		}
+	}
+
+	return issues
+}
+
+func pointerOrRoot(pointer string) string {
+	if pointer == "" {
+		return "/"
+	}
+	return pointer
+}
+
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	if node.Kind == yaml.AliasNode && node.Alias != nil {
+		return node.Alias
+	}
+	return node
+}
+
+func nodeMatchesType(node *yaml.Node, want string) bool {
+	switch want {
+	case "object":
+		return node.Kind == yaml.MappingNode
+	case "array":
+		return node.Kind == yaml.SequenceNode
+	case "string":
+		return node.Kind == yaml.ScalarNode && node.Tag != "!!bool" && node.Tag != "!!int" && node.Tag != "!!float"
+	case "boolean":
+		return node.Kind == yaml.ScalarNode && node.Tag == "!!bool"
+	case "integer":
+		if node.Kind != yaml.ScalarNode {
+			return false
+		}
+		_, err := strconv.Atoi(node.Value)
+		return err == nil
+	default:
+		return true
+	}
+}
+
+func yamlKind(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "object"
+	case yaml.SequenceNode:
+		return "array"
+	case yaml.ScalarNode:
+		return strings.TrimPrefix(node.Tag, "!!")
+	default:
+		return "unknown"
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// nearestMatch returns the candidate with the smallest Levenshtein distance
+// to s, used for "did you mean" suggestions on unknown provider/model/enum
+// values. Returns "" if candidates is empty or the best match's distance
+// exceeds half of s's length plus one (too weak a match to be worth
+// suggesting).
+func nearestMatch(s string, candidates []string) string {
+	best := ""
+	bestDist := -1
+	for _, c := range candidates {
+		d := levenshtein(s, c)
+		if bestDist == -1 || d < bestDist {
+			bestDist = d
+			best = c
+		}
+	}
+	if bestDist == -1 || bestDist > len(s)/2+1 {
+		return ""
+	}
+	return best
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min3(curr[j-1]+1, prev[j]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+func min3(a, b, c int) int {
+	m := a
+	if b < m {
+		m = b
+	}
+	if c < m {
+		m = c
+	}
+	return m
+}
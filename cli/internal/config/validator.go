@@ -4,20 +4,40 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/providers"
 	"gopkg.in/yaml.v3"
 )
 
-// ValidationResult holds validation results
+// ValidationResult holds validation results. Passed/Warnings/Errors remain
+// plain strings for the simple pass/warn/fail summary views; Issues carries
+// the structured detail (JSON pointer, source line/column, error code,
+// "did you mean" suggestion) that the schema-driven checks in schema.go
+// produce.
 type ValidationResult struct {
 	Passed   []string
 	Warnings []string
 	Errors   []string
+	Issues   []Issue
+}
+
+// addIssue records a structured Issue and mirrors it into the matching
+// Passed/Warnings/Errors bucket so callers that only look at the plain
+// string summaries still see it.
+func (r *ValidationResult) addIssue(issue Issue) {
+	r.Issues = append(r.Issues, issue)
+	switch issue.Severity {
+	case "error":
+		r.Errors = append(r.Errors, issue.String())
+	default:
+		r.Warnings = append(r.Warnings, issue.String())
+	}
 }
 
 // Validator validates configuration files
 type Validator struct {
 	configPath string
 	config     map[string]interface{}
+	root       yaml.Node // document root, kept for schema line/column + pointer lookups
 }
 
 // NewValidator creates a new config validator
@@ -27,37 +47,77 @@ func NewValidator(configPath string) *Validator {
 	}
 }
 
-// Validate validates the configuration file
+// Validate validates the configuration file: YAML syntax, then the embedded
+// JSON Schema (structure/types/enums/required fields), then the cross-field
+// rules that a schema can't express (sample rate alignment, default_provider
+// referencing an enabled provider, barge-in ranges).
 func (v *Validator) Validate() (*ValidationResult, error) {
 	result := &ValidationResult{
 		Passed:   []string{},
 		Warnings: []string{},
 		Errors:   []string{},
 	}
-	
+
 	// Load YAML
 	data, err := os.ReadFile(v.configPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
-	
+
 	// Parse YAML
 	if err := yaml.Unmarshal(data, &v.config); err != nil {
 		return nil, fmt.Errorf("invalid YAML syntax: %w", err)
 	}
-	
+	if err := yaml.Unmarshal(data, &v.root); err != nil {
+		return nil, fmt.Errorf("invalid YAML syntax: %w", err)
+	}
+
 	result.Passed = append(result.Passed, "YAML syntax valid")
-	
+
+	v.validateSchema(result)
+
 	// Validate structure
 	v.validateStructure(result)
 	v.validateProviders(result)
 	v.validateSampleRates(result)
 	v.validateTransport(result)
 	v.validateBargeIn(result)
-	
+
 	return result, nil
 }
 
+// validateSchema runs the embedded JSON Schema (pinned via a top-level
+// "$schema" key, defaulting to DefaultSchemaVersion) against the document.
+func (v *Validator) validateSchema(result *ValidationResult) {
+	if len(v.root.Content) == 0 {
+		return
+	}
+	docRoot := v.root.Content[0]
+
+	pin, _ := v.config["$schema"].(string)
+	schema, err := LoadSchema(pin)
+	if err != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("Schema validation skipped: %v", err))
+		return
+	}
+
+	issues := validateAgainstSchema(schema, docRoot, "")
+	if len(issues) == 0 {
+		result.Passed = append(result.Passed, fmt.Sprintf("Matches config schema %s", pinOrDefault(pin)))
+		return
+	}
+	for _, issue := range issues {
+		result.addIssue(issue)
+	}
+}
+
+func pinOrDefault(pin string) string {
+	if pin == "" {
+		return DefaultSchemaVersion
+	}
+	return pin
+}
+
 // validateStructure checks required top-level fields
 func (v *Validator) validateStructure(result *ValidationResult) {
 	required := []string{"default_provider", "providers"}
@@ -76,55 +136,58 @@ func (v *Validator) validateStructure(result *ValidationResult) {
 	}
 }
 
-// validateProviders checks provider configurations
+// validateProviders checks provider configurations against the providers
+// registry (providers.Get/providers.Names) instead of a hardcoded name
+// list, so a new provider only needs registering once, in its own
+// providers/*.go file.
 func (v *Validator) validateProviders(result *ValidationResult) {
-	providers, ok := v.config["providers"].(map[string]interface{})
+	providerConfigs, ok := v.config["providers"].(map[string]interface{})
 	if !ok {
 		result.Errors = append(result.Errors, "Invalid 'providers' structure")
 		return
 	}
-	
-	validProviders := map[string]bool{
-		"openai_realtime": true,
-		"deepgram":        true,
-		"local":           true,
-		"openai":          true,
-		"google_live":     true,
-	}
-	
+
+	knownProviderNames := providers.Names()
 	hasEnabled := false
-	
-	for name, config := range providers {
-		if !validProviders[name] {
-			result.Warnings = append(result.Warnings, fmt.Sprintf("Unknown provider: %s", name))
+
+	for name, rawConfig := range providerConfigs {
+		descriptor := providers.Get(name)
+		if descriptor == nil {
+			result.addIssue(Issue{
+				Code:       "CFG-UNKNOWN-PROVIDER",
+				Severity:   "warning",
+				Pointer:    "/providers/" + name,
+				Message:    fmt.Sprintf("Unknown provider: %s", name),
+				Suggestion: nearestMatch(name, knownProviderNames),
+			})
 			continue
 		}
-		
-		providerConfig, ok := config.(map[string]interface{})
+
+		providerConfig, ok := rawConfig.(map[string]interface{})
 		if !ok {
 			result.Errors = append(result.Errors, fmt.Sprintf("Invalid config for provider: %s", name))
 			continue
 		}
-		
+
 		// Check enabled flag
 		enabled, ok := providerConfig["enabled"].(bool)
 		if ok && enabled {
 			hasEnabled = true
 			result.Passed = append(result.Passed, fmt.Sprintf("Provider '%s' enabled", name))
 		}
-		
+
 		// Check required provider-specific fields
-		v.validateProviderConfig(name, providerConfig, result)
+		v.validateProviderConfig(descriptor, providerConfig, result)
 	}
-	
+
 	if !hasEnabled {
 		result.Warnings = append(result.Warnings, "No providers are enabled")
 	}
-	
+
 	// Validate default_provider exists and is enabled
 	defaultProvider, ok := v.config["default_provider"].(string)
 	if ok {
-		if providerConfig, exists := providers[defaultProvider]; exists {
+		if providerConfig, exists := providerConfigs[defaultProvider]; exists {
 			if pc, ok := providerConfig.(map[string]interface{}); ok {
 				if enabled, ok := pc["enabled"].(bool); ok && enabled {
 					result.Passed = append(result.Passed, fmt.Sprintf("Default provider '%s' is enabled", defaultProvider))
@@ -138,67 +201,73 @@ func (v *Validator) validateProviders(result *ValidationResult) {
 	}
 }
 
-// validateProviderConfig validates provider-specific configuration
-func (v *Validator) validateProviderConfig(provider string, config map[string]interface{}, result *ValidationResult) {
-	switch provider {
-	case "openai_realtime":
-		if model, ok := config["model"].(string); ok {
-			if model == "gpt-4o-realtime-preview-2024-10-01" || model == "gpt-4o-realtime-preview" {
-				result.Passed = append(result.Passed, fmt.Sprintf("OpenAI model '%s' valid", model))
-			} else {
-				result.Warnings = append(result.Warnings, fmt.Sprintf("OpenAI model '%s' may be outdated", model))
-			}
-		}
-		
-	case "deepgram":
-		if model, ok := config["model"].(string); ok {
-			validModels := map[string]bool{
-				"nova-2":           true,
-				"nova-2-general":   true,
-				"nova-2-phonecall": true,
-				"nova":             true,
-			}
-			if validModels[model] {
-				result.Passed = append(result.Passed, fmt.Sprintf("Deepgram model '%s' valid", model))
-			} else {
-				result.Warnings = append(result.Warnings, fmt.Sprintf("Deepgram model '%s' may be invalid", model))
-			}
-		}
-		
-	case "google_live":
-		// Accept either legacy 'model' or current 'llm_model' naming.
-		if model, ok := config["model"].(string); ok {
-			if model == "models/gemini-2.0-flash-exp" {
-				result.Passed = append(result.Passed, "Google model valid")
-			} else {
-				result.Warnings = append(result.Warnings, fmt.Sprintf("Google model '%s' may be outdated", model))
-			}
-		} else if llmModel, ok := config["llm_model"].(string); ok {
-			if llmModel != "" {
-				result.Passed = append(result.Passed, fmt.Sprintf("Google llm_model '%s' configured", llmModel))
-			}
+// validateProviderConfig validates the model id(s) present in config
+// against descriptor.Models, replacing the old per-provider switch.
+func (v *Validator) validateProviderConfig(descriptor *providers.Descriptor, config map[string]interface{}, result *ValidationResult) {
+	modelIDs := descriptor.ModelIDs()
+
+	// google_live accepts either legacy 'model' or current 'llm_model'; an
+	// llm_model isn't checked against the model list since it's meant to be
+	// operator-chosen (any Gemini model id, not just the ones pinned here).
+	key := "model"
+	model, ok := config[key].(string)
+	if !ok && descriptor.Name == "google_live" {
+		if llmModel, ok := config["llm_model"].(string); ok && llmModel != "" {
+			result.Passed = append(result.Passed, fmt.Sprintf("%s llm_model '%s' configured", descriptor.DisplayName, llmModel))
 		}
+		return
+	}
+	if !ok || model == "" {
+		return
+	}
+
+	info, known := descriptor.ModelInfo(model)
+	switch {
+	case known && info.Deprecated:
+		result.addIssue(Issue{
+			Code:       "CFG-DEPRECATED-MODEL",
+			Severity:   "warning",
+			Pointer:    fmt.Sprintf("/providers/%s/%s", descriptor.Name, key),
+			Message:    fmt.Sprintf("%s model '%s' is deprecated", descriptor.DisplayName, model),
+			Suggestion: info.ReplacedBy,
+		})
+	case known:
+		result.Passed = append(result.Passed, fmt.Sprintf("%s model '%s' valid", descriptor.DisplayName, model))
+	case len(modelIDs) == 0:
+		// Descriptor doesn't enumerate models (e.g. google_live's llm_model
+		// is operator-chosen); accept anything non-empty.
+		result.Passed = append(result.Passed, fmt.Sprintf("%s %s '%s' configured", descriptor.DisplayName, key, model))
+	default:
+		result.addIssue(Issue{
+			Code:       "CFG-UNKNOWN-MODEL",
+			Severity:   "warning",
+			Pointer:    fmt.Sprintf("/providers/%s/%s", descriptor.Name, key),
+			Message:    fmt.Sprintf("%s model '%s' may be invalid", descriptor.DisplayName, model),
+			Suggestion: nearestMatch(model, modelIDs),
+		})
 	}
 }
 
-// validateSampleRates checks sample rate alignment
+// validateSampleRates checks sample rate alignment, and - where a
+// descriptor pins one (Descriptor.SampleRateHz) - that the provider is
+// actually running at its required rate.
 func (v *Validator) validateSampleRates(result *ValidationResult) {
-	providers, ok := v.config["providers"].(map[string]interface{})
+	providerConfigs, ok := v.config["providers"].(map[string]interface{})
 	if !ok {
 		return
 	}
-	
-	for name, config := range providers {
-		providerConfig, ok := config.(map[string]interface{})
+
+	for name, rawConfig := range providerConfigs {
+		providerConfig, ok := rawConfig.(map[string]interface{})
 		if !ok {
 			continue
 		}
-		
+
 		enabled, _ := providerConfig["enabled"].(bool)
 		if !enabled {
 			continue
 		}
-		
+
 		// Check sample rate consistency (support both provider_* and plain input/output keys)
 		inputRate, hasInput := providerConfig["provider_input_sample_rate_hz"].(int)
 		if !hasInput {
@@ -208,7 +277,7 @@ func (v *Validator) validateSampleRates(result *ValidationResult) {
 		if !hasOutput {
 			outputRate, hasOutput = providerConfig["output_sample_rate_hz"].(int)
 		}
-		
+
 		if hasInput && hasOutput {
 			if inputRate != outputRate {
 				result.Warnings = append(result.Warnings,
@@ -218,6 +287,13 @@ func (v *Validator) validateSampleRates(result *ValidationResult) {
 					fmt.Sprintf("Provider '%s': sample rates aligned (%d Hz)", name, inputRate))
 			}
 		}
+
+		if descriptor := providers.Get(name); descriptor != nil && descriptor.SampleRateHz != 0 {
+			if hasInput && inputRate != descriptor.SampleRateHz {
+				result.Warnings = append(result.Warnings,
+					fmt.Sprintf("Provider '%s': rate %d Hz doesn't match its required %d Hz", name, inputRate, descriptor.SampleRateHz))
+			}
+		}
 	}
 }
 
@@ -264,13 +340,3 @@ func (v *Validator) validateBargeIn(result *ValidationResult) {
 		}
 	}
 }
-
-// AutoFix attempts to fix common issues
-func (v *Validator) AutoFix(result *ValidationResult) (int, error) {
-	fixed := 0
-	
-	// For now, auto-fix is limited - most issues require manual intervention
-	// This is a placeholder for future enhancement
-	
-	return fixed, nil
-}
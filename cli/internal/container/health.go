@@ -0,0 +1,65 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// WaitHealthy polls m.Status(name) with exponential backoff (starting at
+// 500ms, doubling up to 5s) until the container reports Health=="healthy"
+// (or, for containers with no healthcheck, simply Running), or timeout
+// elapses.
+func WaitHealthy(ctx context.Context, m Manager, name string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	delay := 500 * time.Millisecond
+	const maxDelay = 5 * time.Second
+
+	for {
+		status, err := m.Status(ctx, name)
+		if err == nil {
+			if status.Health == "healthy" || (status.Health == "" && status.Running) {
+				return nil
+			}
+			if status.Health == "unhealthy" {
+				return fmt.Errorf("container %s reported unhealthy", name)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("container %s did not become healthy within %s: %w", name, timeout, ctx.Err())
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+		if delay > maxDelay {
+			delay = maxDelay
+		}
+	}
+}
+
+// NeedsRebuild reports whether service's running container's image digest
+// differs from targetImage's current digest - RebuildContainers uses this
+// to skip a build+recreate when the target is already current.
+func NeedsRebuild(ctx context.Context, m Manager, containerName, targetImage string) (bool, error) {
+	status, err := m.Status(ctx, containerName)
+	if err != nil {
+		return true, err // unknown state - safer to rebuild than to skip
+	}
+	if !status.Running {
+		return true, nil
+	}
+
+	running, err := m.ImageDigest(ctx, status.ImageID)
+	if err != nil || running == "" {
+		return true, nil // can't tell, rebuild to be safe
+	}
+	target, err := m.ImageDigest(ctx, targetImage)
+	if err != nil || target == "" {
+		return true, nil
+	}
+	return running != target, nil
+}
@@ -0,0 +1,151 @@
+package container
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// engineManager talks to a Docker Engine API-compatible daemon (Docker
+// itself, or Podman's Docker-compatible socket) over a unix socket via
+// plain HTTP, rather than a vendored SDK. The API shape is the same for
+// both, so one implementation covers the "docker" and "podman" registry
+// entries - only the socket path and the name reported by Engine() differ.
+type engineManager struct {
+	engine     string
+	socketPath string
+	client     *http.Client
+	apiVersion string
+}
+
+func newEngineManager(engine, socketPath string) *engineManager {
+	return &engineManager{
+		engine:     engine,
+		socketPath: socketPath,
+		apiVersion: "v1.41", // oldest API version both Docker and Podman speak
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					var d net.Dialer
+					return d.DialContext(ctx, "unix", socketPath)
+				},
+			},
+		},
+	}
+}
+
+func (m *engineManager) Engine() string { return m.engine }
+
+func (m *engineManager) url(path string) string {
+	return fmt.Sprintf("http://unix/%s%s", m.apiVersion, path)
+}
+
+func (m *engineManager) wrap(op string, err error) error {
+	if err == nil {
+		return nil
+	}
+	_, unreachable := err.(*net.OpError)
+	return &EngineError{Engine: m.engine, Op: op, Unreachable: unreachable, Err: err}
+}
+
+func (m *engineManager) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, m.url("/_ping"), nil)
+	if err != nil {
+		return m.wrap("ping", err)
+	}
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return m.wrap("ping", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return m.wrap("ping", fmt.Errorf("unexpected status %d", resp.StatusCode))
+	}
+	return nil
+}
+
+type containerInspect struct {
+	Image string `json:"Image"`
+	State struct {
+		Running bool `json:"Running"`
+		Health  *struct {
+			Status string `json:"Status"`
+		} `json:"Health"`
+	} `json:"State"`
+}
+
+func (m *engineManager) Status(ctx context.Context, name string) (*Status, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, m.url("/containers/"+name+"/json"), nil)
+	if err != nil {
+		return nil, m.wrap("status", err)
+	}
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return nil, m.wrap("status", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return &Status{Name: name, Running: false}, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, m.wrap("status", fmt.Errorf("unexpected status %d", resp.StatusCode))
+	}
+
+	var inspect containerInspect
+	if err := json.NewDecoder(resp.Body).Decode(&inspect); err != nil {
+		return nil, m.wrap("status", err)
+	}
+	status := &Status{Name: name, Running: inspect.State.Running, ImageID: inspect.Image}
+	if inspect.State.Health != nil {
+		status.Health = inspect.State.Health.Status
+	}
+	return status, nil
+}
+
+type imageInspect struct {
+	RepoDigests []string `json:"RepoDigests"`
+	ID          string   `json:"Id"`
+}
+
+func (m *engineManager) ImageDigest(ctx context.Context, image string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, m.url("/images/"+image+"/json"), nil)
+	if err != nil {
+		return "", m.wrap("image-digest", err)
+	}
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return "", m.wrap("image-digest", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", m.wrap("image-digest", fmt.Errorf("unexpected status %d", resp.StatusCode))
+	}
+
+	var inspect imageInspect
+	if err := json.NewDecoder(resp.Body).Decode(&inspect); err != nil {
+		return "", m.wrap("image-digest", err)
+	}
+	if len(inspect.RepoDigests) > 0 {
+		return inspect.RepoDigests[0], nil
+	}
+	return inspect.ID, nil
+}
+
+func (m *engineManager) Build(ctx context.Context, service string, onProgress func(BuildProgress)) error {
+	// The Engine API's /build endpoint takes a tar of the build context as
+	// its request body; this compose-service build (Dockerfile resolution,
+	// context selection, multi-stage args) is exactly what `docker compose
+	// build` already does and isn't something worth re-deriving a tar
+	// pipeline for here, so Build delegates through the shell manager - the
+	// streamed-progress contract (onProgress, BuildProgress) is what
+	// callers rely on, not which engine draws the tar together.
+	return newShellManager(m.engine).Build(ctx, service, onProgress)
+}
+
+func (m *engineManager) Up(ctx context.Context, service string) error {
+	return newShellManager(m.engine).Up(ctx, service)
+}
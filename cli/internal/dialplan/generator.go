@@ -2,67 +2,256 @@ package dialplan
 
 import (
 	"fmt"
+	"os/exec"
+	"regexp"
+	"sort"
 	"strings"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/providers"
 )
 
-// Context represents a dialplan context
+// Context represents a dialplan context generated for one provider. Beyond
+// the original single-context snippet, a Context can now carry a DID
+// routing table, a DTMF-driven provider menu, a fallback chain to try if
+// the primary provider is unhealthy, a queue handoff, recording, and CDR
+// variable population - the pieces a real deployment's extensions.conf
+// needs beyond the minimal "Stasis() and hang up" snippet.
 type Context struct {
 	Name        string
 	Provider    string
 	AIContext   string
 	Description string
+
+	// DIDs routes specific DID numbers into this context via their own
+	// extension, in addition to the catch-all "s" extension.
+	DIDs []string
+	// DTMFRoutes lets the caller press a digit to pick a different
+	// provider context before Stasis() is entered.
+	DTMFRoutes []DTMFRoute
+	// FallbackProviders are tried in order (via AI_FALLBACK_PROVIDERS) if
+	// the primary provider reports unhealthy; the Stasis app itself is
+	// responsible for reading this variable and failing over.
+	FallbackProviders []string
+	// Queue, if set, hands off to a queue/agent instead of (or after) the
+	// AI agent - e.g. "press 0 for a human".
+	Queue *QueueHandoff
+	// Recording enables MixMonitor() of the call leg.
+	Recording bool
+	// CDRVars are set via Set(CDR(key)=value) for reporting/billing.
+	CDRVars map[string]string
+}
+
+// DTMFRoute maps one DTMF digit to a different provider context.
+type DTMFRoute struct {
+	Digit       string
+	Provider    string
+	Context     string // target context name; if empty, derived the same way getContextForProvider does
+	Description string
+}
+
+// QueueHandoff describes a DTMF-triggered handoff to an Asterisk queue.
+type QueueHandoff struct {
+	Digit     string // e.g. "0" for "press 0 for an agent"
+	QueueName string
+}
+
+// Format selects the dialplan dialect Generate renders.
+type Format string
+
+const (
+	// FormatClassic emits a classic exten => / same => extensions.conf block.
+	FormatClassic Format = "classic"
+	// FormatPJSIP emits a pjsip.conf endpoint stanza pointing at this context,
+	// for deployments that configure trunks/endpoints declaratively instead
+	// of only routing through extensions.conf.
+	FormatPJSIP Format = "pjsip"
+)
+
+// beginMarker/endMarker bound one context's generated block inside a
+// user-maintained extensions.conf, so Merge can find and replace just that
+// block without touching anything the operator added by hand.
+func beginMarker(ctxName string) string {
+	return fmt.Sprintf("; BEGIN asterisk-ai-voice-agent %s", ctxName)
+}
+func endMarker(ctxName string) string {
+	return fmt.Sprintf("; END asterisk-ai-voice-agent %s", ctxName)
+}
+
+// Generator renders one or more Contexts into a full dialplan.
+type Generator struct {
+	Contexts []Context
 }
 
-// GenerateSnippet generates dialplan snippet for a provider
+// NewGenerator builds a Generator for the given contexts.
+func NewGenerator(contexts ...Context) *Generator {
+	return &Generator{Contexts: contexts}
+}
+
+// GenerateSnippet generates a single-context dialplan snippet for a
+// provider, with no DIDs/DTMF/fallback/queue/recording/CDR. Kept for
+// callers that just want the minimal snippet this function has always
+// returned; new deployments should build a Context and use Generator.
 func GenerateSnippet(provider string) string {
 	ctx := getContextForProvider(provider)
-	
+	return NewGenerator(ctx).renderContext(ctx, FormatClassic)
+}
+
+// Generate renders every context in classic dialplan format, in order.
+func (g *Generator) Generate() string {
+	out, _ := g.GenerateFormat(FormatClassic)
+	return out
+}
+
+// GenerateFormat renders every context in the given Format.
+func (g *Generator) GenerateFormat(format Format) (string, error) {
+	var sb strings.Builder
+	for i, ctx := range g.Contexts {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(beginMarker(ctx.Name) + "\n")
+		sb.WriteString(g.renderContext(ctx, format))
+		sb.WriteString(endMarker(ctx.Name) + "\n")
+	}
+	return sb.String(), nil
+}
+
+func (g *Generator) renderContext(ctx Context, format Format) string {
+	switch format {
+	case FormatPJSIP:
+		return renderPJSIPStanza(ctx)
+	default:
+		return renderClassicContext(ctx)
+	}
+}
+
+func renderClassicContext(ctx Context) string {
 	var sb strings.Builder
 	sb.WriteString(fmt.Sprintf("; AI Voice Agent - %s\n", ctx.Description))
 	sb.WriteString(fmt.Sprintf("[%s]\n", ctx.Name))
-	sb.WriteString(fmt.Sprintf("exten => s,1,NoOp(%s)\n", ctx.Description))
-	sb.WriteString(fmt.Sprintf(" same => n,Set(AI_CONTEXT=%s)\n", ctx.AIContext))
-	sb.WriteString(fmt.Sprintf(" same => n,Set(AI_PROVIDER=%s)\n", ctx.Provider))
-	sb.WriteString(" same => n,Stasis(asterisk-ai-voice-agent)\n")
-	sb.WriteString(" same => n,Hangup()\n")
-	
+
+	exts := append([]string{"s"}, ctx.DIDs...)
+	for _, ext := range exts {
+		sb.WriteString(fmt.Sprintf("exten => %s,1,NoOp(%s)\n", ext, ctx.Description))
+		line := 2
+		cdrKeys := make([]string, 0, len(ctx.CDRVars))
+		for key := range ctx.CDRVars {
+			cdrKeys = append(cdrKeys, key)
+		}
+		sort.Strings(cdrKeys)
+		for _, key := range cdrKeys {
+			sb.WriteString(fmt.Sprintf(" same => n,Set(CDR(%s)=%s)\n", key, ctx.CDRVars[key]))
+			line++
+		}
+		sb.WriteString(fmt.Sprintf(" same => n,Set(AI_CONTEXT=%s)\n", ctx.AIContext))
+		sb.WriteString(fmt.Sprintf(" same => n,Set(AI_PROVIDER=%s)\n", ctx.Provider))
+		if len(ctx.FallbackProviders) > 0 {
+			sb.WriteString(fmt.Sprintf(" same => n,Set(AI_FALLBACK_PROVIDERS=%s)\n", strings.Join(ctx.FallbackProviders, ",")))
+		}
+		if ctx.Recording {
+			sb.WriteString(" same => n,MixMonitor(${UNIQUEID}.wav)\n")
+		}
+		for _, route := range ctx.DTMFRoutes {
+			target := route.Context
+			if target == "" {
+				target = getContextForProvider(route.Provider).Name
+			}
+			sb.WriteString(fmt.Sprintf(" same => n,ExecIf($[\"${DTMF_DIGIT}\"=\"%s\"]?Goto(%s,s,1))\n", route.Digit, target))
+		}
+		if ctx.Queue != nil {
+			sb.WriteString(fmt.Sprintf(" same => n,ExecIf($[\"${DTMF_DIGIT}\"=\"%s\"]?Goto(%s,s,1))\n", ctx.Queue.Digit, queueContextName(ctx)))
+		}
+		sb.WriteString(" same => n,Stasis(asterisk-ai-voice-agent)\n")
+		sb.WriteString(" same => n,Hangup()\n")
+	}
+
+	if ctx.Queue != nil {
+		sb.WriteString(fmt.Sprintf("\n[%s]\n", queueContextName(ctx)))
+		sb.WriteString(fmt.Sprintf("exten => s,1,NoOp(Handoff to queue %s)\n", ctx.Queue.QueueName))
+		sb.WriteString(fmt.Sprintf(" same => n,Queue(%s)\n", ctx.Queue.QueueName))
+		sb.WriteString(" same => n,Hangup()\n")
+	}
+
 	return sb.String()
 }
 
-// getContextForProvider returns context info for a provider
-func getContextForProvider(provider string) Context {
-	contexts := map[string]Context{
-		"openai_realtime": {
-			Name:        "from-ai-agent-openai",
-			Provider:    "openai_realtime",
-			AIContext:   "default",
-			Description: "AI Agent - OpenAI Realtime",
-		},
-		"deepgram": {
-			Name:        "from-ai-agent-deepgram",
-			Provider:    "deepgram",
-			AIContext:   "default",
-			Description: "AI Agent - Deepgram",
-		},
-		"local_hybrid": {
-			Name:        "from-ai-agent-hybrid",
-			Provider:    "local_hybrid",
-			AIContext:   "default",
-			Description: "AI Agent - Local Hybrid",
-		},
-		"google_live": {
-			Name:        "from-ai-agent-google",
-			Provider:    "google_live",
-			AIContext:   "default",
-			Description: "AI Agent - Google Live",
-		},
+func queueContextName(ctx Context) string {
+	return ctx.Name + "-queue"
+}
+
+// renderPJSIPStanza emits a pjsip.conf endpoint stanza that routes an
+// inbound endpoint into this context, for deployments that prefer
+// declarative trunk/endpoint config over dialplan-only routing.
+func renderPJSIPStanza(ctx Context) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("; AI Voice Agent - %s (pjsip endpoint)\n", ctx.Description))
+	sb.WriteString(fmt.Sprintf("[%s]\n", ctx.Name))
+	sb.WriteString("type=endpoint\n")
+	sb.WriteString(fmt.Sprintf("context=%s\n", ctx.Name))
+	sb.WriteString("disallow=all\n")
+	sb.WriteString("allow=ulaw,alaw\n")
+	return sb.String()
+}
+
+// Merge parses existingContent, replaces any previously generated block
+// (bounded by "; BEGIN asterisk-ai-voice-agent <ctx>" / "; END ...") for
+// each of g.Contexts in place, appends a new block for any context that
+// isn't already present, and leaves everything else - including the
+// operator's own dialplan - untouched.
+func (g *Generator) Merge(existingContent string) (string, error) {
+	result := existingContent
+	for _, ctx := range g.Contexts {
+		block := beginMarker(ctx.Name) + "\n" + g.renderContext(ctx, FormatClassic) + endMarker(ctx.Name)
+		pattern := regexp.MustCompile(
+			"(?s)" + regexp.QuoteMeta(beginMarker(ctx.Name)) + ".*?" + regexp.QuoteMeta(endMarker(ctx.Name)))
+
+		if pattern.MatchString(result) {
+			result = pattern.ReplaceAllLiteralString(result, block)
+		} else {
+			if !strings.HasSuffix(result, "\n") && result != "" {
+				result += "\n"
+			}
+			result += "\n" + block + "\n"
+		}
+	}
+	return result, nil
+}
+
+// Validate shells out to `asterisk -rx "dialplan reload"` against a running
+// Asterisk instance (intended to be a throwaway dry-run container) to catch
+// syntax errors before an operator copies generated dialplan into
+// production. It requires a reachable `asterisk` CLI binary/socket, which
+// this sandboxed tree does not have - callers should treat a "asterisk: not
+// found"-shaped error as "couldn't check, not as a failure of the dialplan
+// itself.
+func (g *Generator) Validate() (string, error) {
+	path, err := exec.LookPath("asterisk")
+	if err != nil {
+		return "", fmt.Errorf("dialplan validate: no local `asterisk` binary found (%w); run this inside a container with Asterisk installed", err)
 	}
-	
-	if ctx, ok := contexts[provider]; ok {
-		return ctx
+	cmd := exec.Command(path, "-rx", "dialplan reload")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return string(out), fmt.Errorf("dialplan reload failed: %w", err)
 	}
-	
-	// Default/fallback
+	return string(out), nil
+}
+
+// getContextForProvider returns context info for a provider, built from the
+// providers registry (providers.Descriptor.Dialplan) rather than a
+// hardcoded map, so a new provider's dialplan template only needs adding in
+// one place (its own providers/*.go file).
+func getContextForProvider(provider string) Context {
+	if d := providers.Get(provider); d != nil && d.Dialplan.ContextName != "" {
+		return Context{
+			Name:        d.Dialplan.ContextName,
+			Provider:    d.Name,
+			AIContext:   d.Dialplan.AIContext,
+			Description: d.Dialplan.Description,
+		}
+	}
+
+	// Default/fallback for a provider with no registered dialplan template.
 	return Context{
 		Name:        "from-ai-agent",
 		Provider:    provider,
@@ -71,17 +260,11 @@ func getContextForProvider(provider string) Context {
 	}
 }
 
-// GetProviderDisplayName returns friendly name for provider
+// GetProviderDisplayName returns friendly name for provider, from the
+// providers registry.
 func GetProviderDisplayName(provider string) string {
-	names := map[string]string{
-		"openai_realtime": "OpenAI Realtime",
-		"deepgram":        "Deepgram Voice Agent",
-		"local_hybrid":    "Local Hybrid",
-		"google_live":     "Google Live API",
-	}
-	
-	if name, ok := names[provider]; ok {
-		return name
+	if d := providers.Get(provider); d != nil {
+		return d.DisplayName
 	}
 	return provider
 }
@@ -0,0 +1,52 @@
+package dialplan
+
+import "testing"
+
+func TestGenerateIsIdempotentAcrossRuns(t *testing.T) {
+	t.Parallel()
+
+	ctx := Context{
+		Name:        "from-ai-agent",
+		Provider:    "openai",
+		AIContext:   "default",
+		Description: "AI Agent - openai",
+		CDRVars: map[string]string{
+			"zebra": "1",
+			"alpha": "2",
+			"mike":  "3",
+		},
+	}
+
+	first := NewGenerator(ctx).Generate()
+	for i := 0; i < 10; i++ {
+		if got := NewGenerator(ctx).Generate(); got != first {
+			t.Fatalf("Generate() is not deterministic across runs:\nrun 1:\n%s\nrun %d:\n%s", first, i+2, got)
+		}
+	}
+}
+
+func TestMergeIsIdempotentOnUnchangedContext(t *testing.T) {
+	t.Parallel()
+
+	ctx := Context{
+		Name:        "from-ai-agent",
+		Provider:    "openai",
+		AIContext:   "default",
+		Description: "AI Agent - openai",
+		CDRVars:     map[string]string{"b": "1", "a": "2"},
+	}
+	g := NewGenerator(ctx)
+
+	merged, err := g.Merge("; operator's own dialplan\n")
+	if err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+
+	again, err := g.Merge(merged)
+	if err != nil {
+		t.Fatalf("second Merge: %v", err)
+	}
+	if again != merged {
+		t.Fatalf("Merge is not idempotent on an unchanged Context:\nfirst:\n%s\nsecond:\n%s", merged, again)
+	}
+}
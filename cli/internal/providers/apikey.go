@@ -0,0 +1,24 @@
+package providers
+
+import "fmt"
+
+// ValidateAPIKey validates an API key for the given provider by consulting
+// the registry, replacing the old closed switch statement in
+// validator.ValidateAPIKey (kept there, unchanged, as the low-level
+// per-provider implementation each Descriptor.ValidateAPIKey points at -
+// this package can't import validator's dispatcher back without an import
+// cycle, since the provider Descriptors here are themselves built from
+// validator's functions).
+func ValidateAPIKey(provider, apiKey string) error {
+	if apiKey == "" {
+		return fmt.Errorf("API key cannot be empty")
+	}
+	d := Get(provider)
+	if d == nil {
+		return fmt.Errorf("unknown provider: %s", provider)
+	}
+	if d.ValidateAPIKey == nil {
+		return nil
+	}
+	return d.ValidateAPIKey(apiKey)
+}
@@ -0,0 +1,30 @@
+package providers
+
+import "github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/validator"
+
+func init() {
+	Register(Descriptor{
+		Name:        "deepgram",
+		DisplayName: "Deepgram Voice Agent",
+		Models: []ModelInfo{
+			{ID: "nova-2"},
+			{ID: "nova-2-general"},
+			{ID: "nova-2-phonecall"},
+			{ID: "nova"},
+		},
+		RequiredFields: []string{"api_key", "model"},
+		Dialplan: DialplanTemplate{
+			ContextName: "from-ai-agent-deepgram",
+			AIContext:   "default",
+			Description: "AI Agent - Deepgram",
+		},
+		ValidateAPIKey: validator.ValidateDeepgramKey,
+		Probe: func(apiKey, model string) (bool, string, error) {
+			report, err := validator.Probe("deepgram", apiKey, model)
+			if err != nil {
+				return false, "", err
+			}
+			return report.AuthOK && report.Error == "", report.Error, nil
+		},
+	})
+}
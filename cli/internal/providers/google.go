@@ -0,0 +1,29 @@
+package providers
+
+import "github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/validator"
+
+func init() {
+	Register(Descriptor{
+		Name:        "google_live",
+		DisplayName: "Google Live API",
+		Models: []ModelInfo{
+			{ID: "models/gemini-2.0-flash-exp", Deprecated: true, ReplacedBy: "models/gemini-2.0-flash-live-001"},
+			{ID: "models/gemini-2.0-flash-live-001"},
+		},
+		RequiredFields: []string{"api_key"},
+		OptionalFields: []string{"llm_model"},
+		Dialplan: DialplanTemplate{
+			ContextName: "from-ai-agent-google",
+			AIContext:   "default",
+			Description: "AI Agent - Google Live",
+		},
+		ValidateAPIKey: validator.ValidateGoogleKey,
+		Probe: func(apiKey, model string) (bool, string, error) {
+			report, err := validator.Probe("google_live", apiKey, model)
+			if err != nil {
+				return false, "", err
+			}
+			return report.AuthOK && report.Error == "", report.Error, nil
+		},
+	})
+}
@@ -0,0 +1,17 @@
+package providers
+
+func init() {
+	Register(Descriptor{
+		Name:        "local_hybrid",
+		Aliases:     []string{"local"},
+		DisplayName: "Local Hybrid",
+		Dialplan: DialplanTemplate{
+			ContextName: "from-ai-agent-hybrid",
+			AIContext:   "default",
+			Description: "AI Agent - Local Hybrid",
+		},
+		// No Probe, and ValidateAPIKey accepts any key: local_hybrid runs
+		// against local STT/TTS/LLM services, not a cloud API key.
+		ValidateAPIKey: func(apiKey string) error { return nil },
+	})
+}
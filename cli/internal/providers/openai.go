@@ -0,0 +1,30 @@
+package providers
+
+import "github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/validator"
+
+func init() {
+	Register(Descriptor{
+		Name:        "openai_realtime",
+		Aliases:     []string{"openai"},
+		DisplayName: "OpenAI Realtime",
+		Models: []ModelInfo{
+			{ID: "gpt-4o-realtime-preview-2024-10-01", Deprecated: true, ReplacedBy: "gpt-4o-realtime-preview"},
+			{ID: "gpt-4o-realtime-preview"},
+		},
+		RequiredFields: []string{"api_key", "model"},
+		OptionalFields: []string{"voice", "temperature"},
+		Dialplan: DialplanTemplate{
+			ContextName: "from-ai-agent-openai",
+			AIContext:   "default",
+			Description: "AI Agent - OpenAI Realtime",
+		},
+		ValidateAPIKey: validator.ValidateOpenAIKey,
+		Probe: func(apiKey, model string) (bool, string, error) {
+			report, err := validator.Probe("openai_realtime", apiKey, model)
+			if err != nil {
+				return false, "", err
+			}
+			return report.AuthOK && report.Error == "", report.Error, nil
+		},
+	})
+}
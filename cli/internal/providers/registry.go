@@ -0,0 +1,123 @@
+// Package providers is the single source of truth for what AI providers
+// this CLI knows about. Before this package existed, the same closed set of
+// provider names was hardcoded in five places (config.validateProviders,
+// config.validateProviderConfig, dialplan.getContextForProvider,
+// dialplan.GetProviderDisplayName, validator.ValidateAPIKey) - adding a
+// provider meant editing all five and hoping none were missed. Each
+// provider now registers one Descriptor via init() in its own file here;
+// config and dialplan consult this registry instead. validator.ValidateAPIKey
+// keeps its own switch; providers.ValidateAPIKey is the registry-backed
+// equivalent.
+package providers
+
+import "sort"
+
+// ModelInfo is one model id a provider accepts, with deprecation metadata
+// so the config validator's "is deprecated" warnings have somewhere
+// authoritative to read from. config.AutoFix's deprecated-model-id fixer
+// reads its own deprecatedModels table, not this one.
+type ModelInfo struct {
+	ID         string
+	Deprecated bool
+	ReplacedBy string // only meaningful when Deprecated is true
+}
+
+// DialplanTemplate is the subset of dialplan.Context a provider contributes;
+// kept provider-agnostic (no dependency on the dialplan package, which
+// depends on this one) so dialplan.getContextForProvider can build its own
+// Context from it.
+type DialplanTemplate struct {
+	ContextName string
+	AIContext   string
+	Description string
+}
+
+// ProbeFunc reports a deliberately minimal (ok, detail) result instead of a
+// concrete struct, so this package doesn't need to import the validator
+// package's ProbeReport type (the provider files in this package that
+// register these via init() already import validator to build them).
+type ProbeFunc func(apiKey, model string) (ok bool, detail string, err error)
+
+// Descriptor is everything the rest of the CLI needs to know about one
+// provider.
+type Descriptor struct {
+	Name           string // canonical name, e.g. "openai_realtime"
+	Aliases        []string // other accepted spellings (e.g. config's legacy bare "openai")
+	DisplayName    string
+	Models         []ModelInfo
+	RequiredFields []string
+	OptionalFields []string
+	SampleRateHz   int // 0 means "no fixed constraint, just check input==output"
+	Dialplan       DialplanTemplate
+	ValidateAPIKey func(apiKey string) error `json:"-"`
+	Probe          ProbeFunc                 `json:"-"`
+}
+
+var registry = map[string]*Descriptor{}
+
+// Register adds d to the registry, indexed by its canonical name and every
+// alias. Intended to be called from a provider file's init().
+func Register(d Descriptor) {
+	entry := d
+	registry[d.Name] = &entry
+	for _, alias := range d.Aliases {
+		registry[alias] = &entry
+	}
+}
+
+// Get returns the descriptor for name (canonical or alias), or nil if no
+// provider is registered under that name.
+func Get(name string) *Descriptor {
+	return registry[name]
+}
+
+// Names returns every registered canonical provider name (not aliases),
+// sorted, for `providers list` and "did you mean" suggestion candidates.
+func Names() []string {
+	seen := map[string]bool{}
+	var names []string
+	for _, d := range registry {
+		if !seen[d.Name] {
+			seen[d.Name] = true
+			names = append(names, d.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// All returns every registered Descriptor (deduplicated across aliases),
+// sorted by canonical name.
+func All() []*Descriptor {
+	seen := map[string]bool{}
+	var out []*Descriptor
+	for _, name := range Names() {
+		d := registry[name]
+		if !seen[d.Name] {
+			seen[d.Name] = true
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// ModelIDs returns every model id the descriptor accepts (deprecated or
+// not), for the validator's "did you mean" suggestions.
+func (d *Descriptor) ModelIDs() []string {
+	ids := make([]string, 0, len(d.Models))
+	for _, m := range d.Models {
+		ids = append(ids, m.ID)
+	}
+	return ids
+}
+
+// ModelInfo looks up one model id within this descriptor's Models, or
+// returns ok=false if it isn't listed at all.
+func (d *Descriptor) ModelInfo(id string) (ModelInfo, bool) {
+	for _, m := range d.Models {
+		if m.ID == id {
+			return m, true
+		}
+	}
+	return ModelInfo{}, false
+}
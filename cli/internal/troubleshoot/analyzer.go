@@ -0,0 +1,71 @@
+package troubleshoot
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// Analyzer is the pluggable diagnosis backend interface. LLMAnalyzer (OpenAI,
+// Anthropic, Azure, LocalAI/Ollama) and grpcAnalyzer (any external process
+// speaking the AnalyzerService proto) both implement it, so third parties can
+// register their own diagnosis engine without editing this package.
+type Analyzer interface {
+	Analyze(ctx context.Context, analysis *Analysis, logData string) (*LLMDiagnosis, error)
+}
+
+// AnalyzerFactory builds an Analyzer from the process environment. Returning
+// an error (rather than panicking) lets NewAnalyzer surface misconfiguration
+// the same way NewLLMAnalyzer always has.
+type AnalyzerFactory func() (Analyzer, error)
+
+var analyzerRegistry = map[string]AnalyzerFactory{
+	"openai":       newLLMAnalyzerBackend,
+	"anthropic":    newLLMAnalyzerBackend,
+	"azure_openai": newLLMAnalyzerBackend,
+	"localai":      newLLMAnalyzerBackend,
+	"ollama":       newLLMAnalyzerBackend,
+	"grpc":         newGRPCAnalyzer,
+}
+
+// RegisterAnalyzer adds or overrides the factory for a given
+// TROUBLESHOOT_LLM_PROVIDER value. Third parties can call this from an init()
+// in their own package (imported for side effects) to plug in a custom
+// diagnosis engine without forking this module.
+func RegisterAnalyzer(provider string, factory AnalyzerFactory) {
+	analyzerRegistry[provider] = factory
+}
+
+// NewAnalyzer builds the Analyzer selected by TROUBLESHOOT_LLM_PROVIDER (or
+// auto-detected from available API keys, same as NewLLMAnalyzer).
+func NewAnalyzer() (Analyzer, error) {
+	provider := os.Getenv("TROUBLESHOOT_LLM_PROVIDER")
+	if provider == "" {
+		if os.Getenv("OPENAI_API_KEY") != "" {
+			provider = "openai"
+		} else if os.Getenv("ANTHROPIC_API_KEY") != "" {
+			provider = "anthropic"
+		} else {
+			return nil, fmt.Errorf("no LLM provider configured")
+		}
+	}
+
+	factory, ok := analyzerRegistry[provider]
+	if !ok {
+		return nil, fmt.Errorf("unsupported provider: %s", provider)
+	}
+	return factory()
+}
+
+// newLLMAnalyzerBackend adapts the existing LLMAnalyzer (and its
+// NewLLMAnalyzer env-var parsing) to the Analyzer interface.
+func newLLMAnalyzerBackend() (Analyzer, error) {
+	return NewLLMAnalyzer()
+}
+
+// Analyze implements Analyzer for *LLMAnalyzer, delegating to
+// AnalyzeWithLLMCtx so callers get the same cancellation behavior across
+// every registered backend.
+func (llm *LLMAnalyzer) Analyze(ctx context.Context, analysis *Analysis, logData string) (*LLMDiagnosis, error) {
+	return llm.AnalyzeWithLLMCtx(ctx, analysis, logData)
+}
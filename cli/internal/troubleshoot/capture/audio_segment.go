@@ -0,0 +1,168 @@
+package capture
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/google/gopacket/pcapgo"
+)
+
+// PcapPath returns the conventional pcap path for callID in dir — the same
+// path Start builds, so a server that only has a call ID and the capture
+// directory (not a live Session) can still find the file.
+func PcapPath(dir, callID string) string {
+	return filepath.Join(dir, sanitizeCallID(callID)+".pcap")
+}
+
+// ulawToPCM16 is the standard G.711 μ-law decompression table (ITU-T G.711),
+// used to turn captured RTP payload bytes into linear PCM16 samples for
+// ExtractAudioSegment/ComputePeaks.
+var ulawToPCM16 = buildUlawTable()
+
+func buildUlawTable() [256]int16 {
+	var table [256]int16
+	for i := 0; i < 256; i++ {
+		u := ^byte(i)
+		sign := u & 0x80
+		exponent := (u >> 4) & 0x07
+		mantissa := u & 0x0F
+		sample := int32(mantissa)<<3 + 0x84
+		sample <<= exponent
+		sample -= 0x84
+		if sign != 0 {
+			sample = -sample
+		}
+		table[i] = int16(sample)
+	}
+	return table
+}
+
+// ExtractAudioSegment reads pcapPath and returns the linear PCM16 (8kHz
+// mono) samples for RTP packets on rtpPort whose capture time falls within
+// [startMs, endMs) of the capture's start — decoding μ-law payloads via
+// ulawToPCM16. Packets are assumed to carry a standard 12-byte RTP header
+// followed by a G.711 μ-law payload, matching the PCMU codec ai_engine
+// negotiates with Asterisk for ExternalMedia (see rtpClockHz in metrics.go).
+func ExtractAudioSegment(pcapPath string, rtpPort int, startMs, endMs int) ([]int16, error) {
+	f, err := os.Open(pcapPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r, err := pcapgo.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("not a pcap file: %w", err)
+	}
+
+	var (
+		samples    []int16
+		firstStamp time.Time
+	)
+
+	for {
+		data, ci, err := r.ReadPacketData()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			break
+		}
+
+		pkt := gopacket.NewPacket(data, r.LinkType(), gopacket.DecodeOptions{Lazy: true, NoCopy: true})
+		udpLayer := pkt.Layer(layers.LayerTypeUDP)
+		if udpLayer == nil {
+			continue
+		}
+		udp, _ := udpLayer.(*layers.UDP)
+		if rtpPort > 0 && int(udp.DstPort) != rtpPort && int(udp.SrcPort) != rtpPort {
+			continue
+		}
+
+		payload := udp.Payload
+		if len(payload) < 13 {
+			continue // 12-byte RTP header + at least 1 payload byte
+		}
+
+		if firstStamp.IsZero() {
+			firstStamp = ci.Timestamp
+		}
+		offsetMs := int(ci.Timestamp.Sub(firstStamp).Milliseconds())
+		if offsetMs < startMs {
+			continue
+		}
+		if endMs > 0 && offsetMs >= endMs {
+			break
+		}
+
+		for _, b := range payload[12:] {
+			samples = append(samples, ulawToPCM16[b])
+		}
+	}
+
+	return samples, nil
+}
+
+// ComputePeaks downsamples samples into the given number of buckets, each
+// holding the maximum absolute sample value in its span — enough for a
+// front-end waveform view of barge-in/VAD events without shipping every
+// sample.
+func ComputePeaks(samples []int16, buckets int) []int32 {
+	if buckets <= 0 || len(samples) == 0 {
+		return nil
+	}
+	peaks := make([]int32, buckets)
+	bucketSize := float64(len(samples)) / float64(buckets)
+	for b := 0; b < buckets; b++ {
+		start := int(float64(b) * bucketSize)
+		end := int(float64(b+1) * bucketSize)
+		if end > len(samples) {
+			end = len(samples)
+		}
+		var peak int32
+		for _, s := range samples[start:end] {
+			v := int32(s)
+			if v < 0 {
+				v = -v
+			}
+			if v > peak {
+				peak = v
+			}
+		}
+		peaks[b] = peak
+	}
+	return peaks
+}
+
+// ExportWAV wraps PCM16 mono samples at sampleRateHz in a standard 44-byte
+// WAV header, the only AudioFormat GetAudioSegment can produce today (see
+// the AudioFormat enum in proto/rca_data.proto — OPUS/MP3 require an
+// encoder this module doesn't vendor).
+func ExportWAV(samples []int16, sampleRateHz int) []byte {
+	dataSize := len(samples) * 2
+	var buf bytes.Buffer
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, uint32(36+dataSize))
+	buf.WriteString("WAVE")
+	buf.WriteString("fmt ")
+	binary.Write(&buf, binary.LittleEndian, uint32(16))          // fmt chunk size
+	binary.Write(&buf, binary.LittleEndian, uint16(1))           // PCM
+	binary.Write(&buf, binary.LittleEndian, uint16(1))           // mono
+	binary.Write(&buf, binary.LittleEndian, uint32(sampleRateHz))
+	binary.Write(&buf, binary.LittleEndian, uint32(sampleRateHz*2)) // byte rate
+	binary.Write(&buf, binary.LittleEndian, uint16(2))              // block align
+	binary.Write(&buf, binary.LittleEndian, uint16(16))             // bits per sample
+	buf.WriteString("data")
+	binary.Write(&buf, binary.LittleEndian, uint32(dataSize))
+	for _, s := range samples {
+		binary.Write(&buf, binary.LittleEndian, s)
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,132 @@
+package capture
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/google/gopacket/pcapgo"
+)
+
+// PacketCaptureMetrics holds ground-truth metrics computed directly from a
+// pcap, independent of whatever ai_engine itself logged — used to cross-check
+// the log-derived CallMetrics rather than just trust them.
+type PacketCaptureMetrics struct {
+	PcapPath       string  `json:"pcap_path,omitempty"`
+	RTPPacketCount int     `json:"rtp_packet_count,omitempty"`
+	JitterMs       float64 `json:"jitter_ms,omitempty"`
+	LossPercent    float64 `json:"loss_percent,omitempty"`
+	BitrateKbps    float64 `json:"bitrate_kbps,omitempty"`
+}
+
+// rtpClockHz is the RTP timestamp clock rate. ai_engine's ExternalMedia RTP
+// streams negotiate PCMU/PCMA with Asterisk, both fixed at 8kHz; if a future
+// profile negotiates a different codec clock, this will need to become a
+// parameter threaded from the RCAHeader instead.
+const rtpClockHz = 8000
+
+// Analyze parses pcapPath (written by Start/Stop) and computes RTP packet
+// count, RFC 3550 §6.4.1 interarrival jitter
+// (J = J + (|D(i-1,i)| - J)/16), loss percentage from sequence-number gaps,
+// and average bitrate. Non-RTP packets (e.g. the AudioSocket TCP side also
+// matched by the capture filter) are ignored.
+func Analyze(pcapPath string, rtpPort int) (*PacketCaptureMetrics, error) {
+	f, err := os.Open(pcapPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r, err := pcapgo.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("not a pcap file: %w", err)
+	}
+
+	m := &PacketCaptureMetrics{PcapPath: pcapPath}
+
+	var (
+		firstSeq, lastSeq                uint16
+		haveSeq                          bool
+		totalBytes                       int
+		firstCaptureTime, lastCaptureTime time.Time
+		jitter                           float64
+		prevTransit                      float64
+		havePrevTransit                  bool
+	)
+
+	for {
+		data, ci, err := r.ReadPacketData()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			break
+		}
+
+		pkt := gopacket.NewPacket(data, r.LinkType(), gopacket.DecodeOptions{Lazy: true, NoCopy: true})
+		udpLayer := pkt.Layer(layers.LayerTypeUDP)
+		if udpLayer == nil {
+			continue
+		}
+		udp, _ := udpLayer.(*layers.UDP)
+		if rtpPort > 0 && int(udp.DstPort) != rtpPort && int(udp.SrcPort) != rtpPort {
+			continue
+		}
+
+		payload := udp.Payload
+		if len(payload) < 12 {
+			continue // too short to be an RTP header
+		}
+		seq := binary.BigEndian.Uint16(payload[2:4])
+		ts := binary.BigEndian.Uint32(payload[4:8])
+
+		m.RTPPacketCount++
+		totalBytes += len(data)
+		if firstCaptureTime.IsZero() {
+			firstCaptureTime = ci.Timestamp
+		}
+		lastCaptureTime = ci.Timestamp
+
+		if !haveSeq {
+			firstSeq = seq
+			haveSeq = true
+		}
+		lastSeq = seq
+
+		arrival := ci.Timestamp.Sub(firstCaptureTime).Seconds() * rtpClockHz
+		transit := arrival - float64(ts)
+		if havePrevTransit {
+			d := transit - prevTransit
+			if d < 0 {
+				d = -d
+			}
+			jitter += (d - jitter) / 16
+		}
+		prevTransit = transit
+		havePrevTransit = true
+	}
+
+	if m.RTPPacketCount == 0 {
+		return m, nil
+	}
+
+	m.JitterMs = jitter / rtpClockHz * 1000
+
+	// uint16 sequence number arithmetic wraps; for a single call's duration
+	// this simple difference is accurate unless more than ~32k packets are
+	// lost in a row, which would indicate a much bigger problem anyway.
+	expected := int(lastSeq-firstSeq) + 1
+	if expected > 0 && expected >= m.RTPPacketCount {
+		m.LossPercent = float64(expected-m.RTPPacketCount) / float64(expected) * 100
+	}
+
+	if dur := lastCaptureTime.Sub(firstCaptureTime).Seconds(); dur > 0 {
+		m.BitrateKbps = float64(totalBytes) * 8 / dur / 1000
+	}
+
+	return m, nil
+}
@@ -0,0 +1,96 @@
+// Package capture spawns tcpdump to record the RTP/AudioSocket traffic of a
+// single call and computes ground-truth packet metrics from the resulting
+// pcap, so `agent rca --follow --capture` can cross-check what ai_engine
+// logged against what actually went out on the wire.
+package capture
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Options configures a capture scoped to one call. At least one of RTPPort
+// or AudioSocketPort must be set.
+type Options struct {
+	RTPPort         int    // Header.ExternalMediaRTPPort
+	AudioSocketPort int    // Header.AudioSocketPort
+	OutputDir       string // where to write the .pcap; default os.TempDir()
+}
+
+func (o Options) filterExpr() (string, error) {
+	var parts []string
+	if o.RTPPort > 0 {
+		parts = append(parts, fmt.Sprintf("udp port %d", o.RTPPort))
+	}
+	if o.AudioSocketPort > 0 {
+		parts = append(parts, fmt.Sprintf("tcp port %d", o.AudioSocketPort))
+	}
+	if len(parts) == 0 {
+		return "", fmt.Errorf("capture requires a known RTP or AudioSocket port")
+	}
+	return strings.Join(parts, " or "), nil
+}
+
+// Session is one running tcpdump capture, scoped to a single call.
+type Session struct {
+	cmd      *exec.Cmd
+	pcapPath string
+}
+
+// Start spawns `tcpdump -i any -w <dir>/<call_id>.pcap <filter>` for the
+// lifetime of the call. The caller must call Stop once the call ends so the
+// pcap is flushed and ready to analyze.
+func Start(ctx context.Context, callID string, opts Options) (*Session, error) {
+	filter, err := opts.filterExpr()
+	if err != nil {
+		return nil, err
+	}
+	dir := opts.OutputDir
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create capture dir: %w", err)
+	}
+	path := filepath.Join(dir, sanitizeCallID(callID)+".pcap")
+
+	cmd := exec.CommandContext(ctx, "tcpdump", "-i", "any", "-w", path, filter)
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start tcpdump: %w", err)
+	}
+	return &Session{cmd: cmd, pcapPath: path}, nil
+}
+
+// Stop asks tcpdump to flush and exit cleanly (SIGINT, matching how you'd
+// Ctrl-C it by hand) and waits for it, falling back to a hard kill if it
+// doesn't exit quickly.
+func (s *Session) Stop() error {
+	if s.cmd.Process != nil {
+		_ = s.cmd.Process.Signal(syscall.SIGINT)
+	}
+	done := make(chan error, 1)
+	go func() { done <- s.cmd.Wait() }()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(5 * time.Second):
+		_ = s.cmd.Process.Kill()
+		return fmt.Errorf("tcpdump did not exit after SIGINT; killed")
+	}
+}
+
+// PcapPath returns where the capture was (or is being) written, so it can be
+// saved next to the JSON report and opened in Wireshark later.
+func (s *Session) PcapPath() string {
+	return s.pcapPath
+}
+
+func sanitizeCallID(callID string) string {
+	return strings.NewReplacer("/", "_", ":", "_", " ", "_").Replace(callID)
+}
@@ -0,0 +1,50 @@
+package troubleshoot
+
+import (
+	"fmt"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/troubleshoot/capture"
+)
+
+// defaultCaptureLossTolerancePercent/defaultCaptureJitterToleranceMs are the
+// --capture cross-check defaults: how far tcpdump's ground-truth RTP metrics
+// can disagree with what ai_engine logged before it's worth a warning.
+const (
+	defaultCaptureLossTolerancePercent = 1.0
+	defaultCaptureJitterToleranceMs    = 20.0
+)
+
+// CrossCheckCapture compares --capture's ground-truth PacketCaptureMetrics
+// against the log-derived CallMetrics and returns warning lines for any
+// disagreement beyond tolerance.
+//
+// CallMetrics doesn't carry a numeric log-derived jitter value today (only
+// the *_jitter_buffer_ms config and a jitter-buffer-underflow count), so
+// these checks compare against the nearest available signal — underflow and
+// gate-closure counts — rather than jitter-to-jitter. A provider logging
+// zero underflows while tcpdump measures real loss/jitter is exactly the
+// "provider isn't computing it correctly" case this is meant to catch.
+func CrossCheckCapture(metrics *CallMetrics, pcm *capture.PacketCaptureMetrics, lossTolerancePercent, jitterToleranceMs float64) []string {
+	if metrics == nil || pcm == nil || pcm.RTPPacketCount == 0 {
+		return nil
+	}
+	if lossTolerancePercent <= 0 {
+		lossTolerancePercent = defaultCaptureLossTolerancePercent
+	}
+	if jitterToleranceMs <= 0 {
+		jitterToleranceMs = defaultCaptureJitterToleranceMs
+	}
+
+	var warnings []string
+	if pcm.LossPercent > lossTolerancePercent && metrics.UnderflowCount == 0 {
+		warnings = append(warnings, fmt.Sprintf(
+			"⚠️  Capture/log mismatch: tcpdump measured %.2f%% RTP packet loss but ai_engine logged zero jitter-buffer underflows — check provider-side loss concealment or RTP reassembly",
+			pcm.LossPercent))
+	}
+	if pcm.JitterMs > jitterToleranceMs && metrics.UnderflowCount == 0 && metrics.GateClosures == 0 {
+		warnings = append(warnings, fmt.Sprintf(
+			"⚠️  Capture/log mismatch: tcpdump measured %.1fms of RTP interarrival jitter but ai_engine reported no jitter-buffer underflows or gate closures — the provider may not be computing jitter correctly",
+			pcm.JitterMs))
+	}
+	return warnings
+}
@@ -0,0 +1,158 @@
+package troubleshoot
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"sync"
+)
+
+// Explainer wraps an Analyzer with the anonymization and response-caching
+// behavior needed to safely send findings to a hosted LLM: every call site
+// that wants --explain semantics (scrub PII before it leaves the box, don't
+// pay for the same diagnosis twice) should go through NewExplainer rather
+// than calling Analyzer.Analyze directly. The rule-based Analysis already
+// computed by SymptomChecker remains the ground truth; this only adds an
+// optional, anonymized LLM narration on top of it, same as --llm already did.
+type Explainer interface {
+	Explain(ctx context.Context, analysis *Analysis, logData string) (*LLMDiagnosis, error)
+}
+
+var (
+	sipURIPattern = regexp.MustCompile(`\bsips?:[^\s;,>"']+`)
+	phoneNumberRe = regexp.MustCompile(`\+?\d[\d\-. ]{8,14}\d`)
+	ipAddressRe   = regexp.MustCompile(`\b\d{1,3}\.\d{1,3}\.\d{1,3}\.\d{1,3}\b`)
+	apiKeyLikeRe  = regexp.MustCompile(`\b(?:sk|pk|rk)-[A-Za-z0-9_-]{8,}\b|\bBearer\s+[A-Za-z0-9._-]{8,}\b`)
+)
+
+// anonymize strips SIP URIs, phone numbers, IP addresses, and API-key-like
+// tokens from s before it is handed to a hosted LLM. It is intentionally
+// pattern-based rather than exhaustive NLP-grade PII detection: the goal is
+// to keep obviously-identifying call metadata out of a third-party request,
+// not to guarantee zero leakage.
+func anonymize(s string) string {
+	s = sipURIPattern.ReplaceAllString(s, "sip:<redacted>")
+	s = apiKeyLikeRe.ReplaceAllString(s, "<redacted-key>")
+	s = ipAddressRe.ReplaceAllString(s, "<redacted-ip>")
+	s = phoneNumberRe.ReplaceAllString(s, "<redacted-number>")
+	return s
+}
+
+// anonymizeAnalysis returns a copy of analysis with every free-text field an
+// LLM would see (findings, root causes, tool-call arguments) anonymized. The
+// underlying metrics/analysis passed to callers and persisted reports are
+// untouched; only the copy handed to the Explainer is scrubbed.
+func anonymizeAnalysis(analysis *Analysis) *Analysis {
+	if analysis == nil {
+		return nil
+	}
+	clone := *analysis
+	if analysis.SymptomAnalysis != nil {
+		sa := *analysis.SymptomAnalysis
+		sa.Findings = make([]string, len(analysis.SymptomAnalysis.Findings))
+		for i, f := range analysis.SymptomAnalysis.Findings {
+			sa.Findings[i] = anonymize(f)
+		}
+		sa.RootCauses = make([]string, len(analysis.SymptomAnalysis.RootCauses))
+		for i, rc := range analysis.SymptomAnalysis.RootCauses {
+			sa.RootCauses[i] = anonymize(rc)
+		}
+		clone.SymptomAnalysis = &sa
+	}
+	if len(analysis.ToolCalls) > 0 {
+		clone.ToolCalls = make([]ToolCallRecord, len(analysis.ToolCalls))
+		for i, tc := range analysis.ToolCalls {
+			tc.Arguments = "<redacted>"
+			clone.ToolCalls[i] = tc
+		}
+	}
+	return &clone
+}
+
+// explainCache memoizes LLMDiagnosis responses by a hash of the anonymized
+// request, so --explain (usually run repeatedly via --watch/--follow against
+// overlapping log windows) doesn't pay for the same diagnosis twice in one
+// process lifetime. It is deliberately in-memory only: there is no ask here
+// for a durable cache, and the existing --save SQLite store already persists
+// the diagnosis that was actually returned.
+type explainCache struct {
+	mu    sync.Mutex
+	byKey map[string]*LLMDiagnosis
+}
+
+func (c *explainCache) get(key string) (*LLMDiagnosis, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	d, ok := c.byKey[key]
+	return d, ok
+}
+
+func (c *explainCache) put(key string, d *LLMDiagnosis) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.byKey == nil {
+		c.byKey = make(map[string]*LLMDiagnosis)
+	}
+	c.byKey[key] = d
+}
+
+var sharedExplainCache = &explainCache{}
+
+// cachingExplainer anonymizes its input and memoizes the backend's response
+// by a hash of the anonymized findings before delegating to backend.Analyze.
+type cachingExplainer struct {
+	backend Analyzer
+	cache   *explainCache
+}
+
+// NewExplainer wraps backend (typically the result of NewAnalyzer) so its
+// Explain calls anonymize findings before they leave the process and cache
+// responses by a hash of the sanitized input.
+func NewExplainer(backend Analyzer) Explainer {
+	return &cachingExplainer{backend: backend, cache: sharedExplainCache}
+}
+
+func (e *cachingExplainer) Explain(ctx context.Context, analysis *Analysis, logData string) (*LLMDiagnosis, error) {
+	sanitizedAnalysis := anonymizeAnalysis(analysis)
+	sanitizedLog := anonymize(logData)
+
+	key := explainCacheKey(sanitizedAnalysis, sanitizedLog)
+	if cached, ok := e.cache.get(key); ok {
+		return cached, nil
+	}
+
+	diagnosis, err := e.backend.Analyze(ctx, sanitizedAnalysis, sanitizedLog)
+	if err != nil {
+		return nil, err
+	}
+	e.cache.put(key, diagnosis)
+	return diagnosis, nil
+}
+
+// explainCacheKey hashes the sanitized findings that will actually be sent
+// to the LLM, so two calls with identical symptoms/root-causes/log text
+// share a cache entry even if they come from different call IDs.
+func explainCacheKey(analysis *Analysis, logData string) string {
+	h := sha256.New()
+	if analysis != nil && analysis.SymptomAnalysis != nil {
+		for _, f := range analysis.SymptomAnalysis.Findings {
+			h.Write([]byte(f))
+			h.Write([]byte{0})
+		}
+		for _, rc := range analysis.SymptomAnalysis.RootCauses {
+			h.Write([]byte(rc))
+			h.Write([]byte{0})
+		}
+	}
+	h.Write([]byte(logData))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// displayExplanation renders diagnosis in the same colored TTY style as
+// displayLLMDiagnosis, labeling it as an anonymized explanation so operators
+// know PII was scrubbed before this left the box.
+func (r *Runner) displayExplanation(diagnosis *LLMDiagnosis) {
+	warningColor.Println("(anonymized before sending to LLM)")
+	r.displayLLMDiagnosis(diagnosis)
+}
@@ -0,0 +1,67 @@
+package troubleshoot
+
+import "strings"
+
+// FieldMask is a compiled set of dotted field-path patterns (e.g.
+// "name,status,arguments.extension,message" or "arguments.*"), used to
+// pick which entries of a flattened field map get projected into a
+// record's Extras.
+type FieldMask struct {
+	patterns [][]string // each pattern, pre-split on '.'
+}
+
+// CompileFieldMask parses a comma-separated mask string into a FieldMask.
+// An empty mask compiles to nil, and a nil *FieldMask matches nothing
+// (Matches is safe to call on it) - ExtractToolCallsWithOptions uses this
+// to tell "no mask given" apart from "mask matched nothing".
+func CompileFieldMask(mask string) *FieldMask {
+	mask = strings.TrimSpace(mask)
+	if mask == "" {
+		return nil
+	}
+	fm := &FieldMask{}
+	for _, part := range strings.Split(mask, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		fm.patterns = append(fm.patterns, strings.Split(part, "."))
+	}
+	return fm
+}
+
+// Matches reports whether key (a dotted field path, e.g.
+// "arguments.extension") is selected by any pattern in the mask. A "*"
+// path segment matches exactly one segment, except as the final segment
+// of a pattern, where it matches one or more remaining segments (so
+// "arguments.*" selects every field under "arguments").
+func (fm *FieldMask) Matches(key string) bool {
+	if fm == nil {
+		return false
+	}
+	keySegs := strings.Split(key, ".")
+	for _, pattern := range fm.patterns {
+		if matchFieldPath(pattern, keySegs) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchFieldPath(pattern, key []string) bool {
+	for i, seg := range pattern {
+		if seg == "*" {
+			if i == len(pattern)-1 {
+				return len(key) > i // trailing wildcard: absorb one or more remaining segments
+			}
+			if i >= len(key) {
+				return false
+			}
+			continue
+		}
+		if i >= len(key) || key[i] != seg {
+			return false
+		}
+	}
+	return len(key) == len(pattern)
+}
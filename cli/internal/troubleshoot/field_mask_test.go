@@ -0,0 +1,62 @@
+package troubleshoot
+
+import "testing"
+
+func TestFieldMaskExactAndWildcard(t *testing.T) {
+	t.Parallel()
+
+	fm := CompileFieldMask("name,status,arguments.*")
+	cases := map[string]bool{
+		"name":                true,
+		"status":              true,
+		"message":             false,
+		"arguments.extension": true,
+		"arguments.retries":   true,
+		"other.extension":     false,
+	}
+	for key, want := range cases {
+		if got := fm.Matches(key); got != want {
+			t.Fatalf("Matches(%q) = %v, want %v", key, got, want)
+		}
+	}
+}
+
+func TestCompileFieldMaskEmptyMatchesNothing(t *testing.T) {
+	t.Parallel()
+
+	fm := CompileFieldMask("")
+	if fm != nil {
+		t.Fatalf("expected nil FieldMask for an empty mask")
+	}
+	if fm.Matches("anything") {
+		t.Fatalf("nil FieldMask should never match")
+	}
+}
+
+func TestExtractToolCallsWithOptionsProjectsMaskedArguments(t *testing.T) {
+	t.Parallel()
+
+	logData := "2026-01-30T17:21:43.227800-07:00 [info     ] 🔧 Deepgram tool call: check_extension_status({'extension': '2765'}) [src.tools.adapters.deepgram] call_id=1769818882.1484 function_call_id=call_AkCimSaNLM4lXmdND1WrA38y\n"
+
+	calls := ExtractToolCallsWithOptions(logData, ExtractOptions{Mask: "arguments.*"})
+	if len(calls) != 1 {
+		t.Fatalf("expected 1 tool call, got %d", len(calls))
+	}
+	if calls[0].Extras["arguments.extension"] != "2765" {
+		t.Fatalf("Extras[arguments.extension]=%q (extras=%#v)", calls[0].Extras["arguments.extension"], calls[0].Extras)
+	}
+}
+
+func TestExtractToolCallsWithOptionsMaxResults(t *testing.T) {
+	t.Parallel()
+
+	logData := "" +
+		"[info     ] tool call: a() [src] function_call_id=1\n" +
+		"[info     ] tool call: b() [src] function_call_id=2\n" +
+		"[info     ] tool call: c() [src] function_call_id=3\n"
+
+	calls := ExtractToolCallsWithOptions(logData, ExtractOptions{MaxResults: 2})
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 tool calls, got %d", len(calls))
+	}
+}
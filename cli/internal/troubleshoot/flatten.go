@@ -0,0 +1,178 @@
+package troubleshoot
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FlattenOptions bounds how deep and how wide flattenValue/DecodeEmbedded
+// walk an arbitrary JSON value, so a hostile or malformed log line (deeply
+// nested objects, huge arrays) can't turn a single parseLogLine call into
+// unbounded work.
+type FlattenOptions struct {
+	MaxDepth  int // 0 uses defaultFlattenMaxDepth
+	MaxFields int // 0 uses defaultFlattenMaxFields
+}
+
+const (
+	defaultFlattenMaxDepth  = 6
+	defaultFlattenMaxFields = 256
+)
+
+func (o FlattenOptions) withDefaults() FlattenOptions {
+	if o.MaxDepth <= 0 {
+		o.MaxDepth = defaultFlattenMaxDepth
+	}
+	if o.MaxFields <= 0 {
+		o.MaxFields = defaultFlattenMaxFields
+	}
+	return o
+}
+
+// flattenKey joins a dotted/bracketed path prefix with the next segment,
+// e.g. flattenKey("usage", "prompt_tokens") -> "usage.prompt_tokens" and
+// flattenKey("messages[0]", "role") -> "messages[0].role".
+func flattenKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// flattenValue walks v (the decoded-JSON shape: map[string]any, []any,
+// string, json.Number, bool, or nil) and writes one entry per leaf into
+// out, keyed by prefix extended with dot/bracket notation. It stops
+// descending once depth or the shared field budget runs out. A subtree cut
+// off by the depth limit gets a single truncation marker in its place;
+// leaves left over once the field budget is spent are dropped unmarked.
+func flattenValue(prefix string, v any, depth int, opts FlattenOptions, out map[string]string, budget *int) {
+	if *budget <= 0 {
+		return
+	}
+
+	switch t := v.(type) {
+	case map[string]any:
+		if depth >= opts.MaxDepth {
+			out[prefix] = "…(truncated, max depth reached)"
+			*budget--
+			return
+		}
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys) // deterministic output regardless of Go's map iteration order
+		for _, k := range keys {
+			if *budget <= 0 {
+				return
+			}
+			flattenValue(flattenKey(prefix, k), t[k], depth+1, opts, out, budget)
+		}
+	case []any:
+		if depth >= opts.MaxDepth {
+			out[prefix] = "…(truncated, max depth reached)"
+			*budget--
+			return
+		}
+		for i, v2 := range t {
+			if *budget <= 0 {
+				return
+			}
+			flattenValue(fmt.Sprintf("%s[%d]", prefix, i), v2, depth+1, opts, out, budget)
+		}
+	case string:
+		out[prefix] = t
+		*budget--
+	case json.Number:
+		out[prefix] = formatJSONNumber(t)
+		*budget--
+	case bool:
+		if t {
+			out[prefix] = "true"
+		} else {
+			out[prefix] = "false"
+		}
+		*budget--
+	case nil:
+		out[prefix] = ""
+		*budget--
+	}
+}
+
+// formatJSONNumber renders a json.Number the same way parseLogLine's JSON
+// path does (trailing zeros trimmed off non-exponent decimals), so a
+// nested numeric field reads the same as a top-level one.
+func formatJSONNumber(n json.Number) string {
+	num := strings.TrimSpace(n.String())
+	if strings.Contains(num, ".") && !strings.ContainsAny(num, "eE") {
+		num = strings.TrimRight(num, "0")
+		num = strings.TrimRight(num, ".")
+	}
+	return num
+}
+
+// FlattenJSON decodes raw (a JSON object or array) and flattens it into a
+// map[string]string under keyPrefix, using opts (zero value is fine - see
+// FlattenOptions.withDefaults). Returns ok=false if raw isn't valid JSON.
+func FlattenJSON(keyPrefix string, raw string, opts FlattenOptions) (map[string]string, bool) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, false
+	}
+	var decoded any
+	dec := json.NewDecoder(strings.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&decoded); err != nil {
+		return nil, false
+	}
+	opts = opts.withDefaults()
+	out := make(map[string]string, 8)
+	budget := opts.MaxFields
+	flattenValue(keyPrefix, decoded, 0, opts, out, &budget)
+	return out, true
+}
+
+// DecodeEmbeddedJSON scans fields for string values that look like
+// embedded JSON (trimmed value starts with '{' or '['), decodes and
+// flattens any that parse successfully, and merges the result back into
+// fields under "<key>.<nested path>". A value that doesn't decode (a
+// quoted natural-language string that merely starts with a brace) is left
+// untouched rather than erroring. This is opt-in (callers choose when the
+// cost of attempting extra JSON decodes per field is worth it) rather than
+// run unconditionally inside parseLogLine.
+func DecodeEmbeddedJSON(fields map[string]string, opts FlattenOptions) {
+	if len(fields) == 0 {
+		return
+	}
+	opts = opts.withDefaults()
+	budget := opts.MaxFields
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	additions := make(map[string]string)
+	for _, k := range keys {
+		if budget <= 0 {
+			return
+		}
+		trimmed := strings.TrimSpace(fields[k])
+		if trimmed == "" || (trimmed[0] != '{' && trimmed[0] != '[') {
+			continue
+		}
+		var decoded any
+		dec := json.NewDecoder(strings.NewReader(trimmed))
+		dec.UseNumber()
+		if dec.Decode(&decoded) != nil {
+			continue
+		}
+		flattenValue(k, decoded, 0, opts, additions, &budget)
+	}
+	for k, v := range additions {
+		fields[k] = v
+	}
+}
@@ -0,0 +1,68 @@
+package troubleshoot
+
+import "testing"
+
+func TestParseLogLineFlattensNestedObjects(t *testing.T) {
+	t.Parallel()
+
+	line := `{"event":"usage","level":"info","usage":{"prompt_tokens":12,"messages":[{"role":"user"}]}}`
+	_, event, fields, ok := parseLogLine(line)
+	if !ok {
+		t.Fatalf("expected parse to succeed")
+	}
+	if event != "usage" {
+		t.Fatalf("event=%q", event)
+	}
+	if fields["usage.prompt_tokens"] != "12" {
+		t.Fatalf("usage.prompt_tokens=%q", fields["usage.prompt_tokens"])
+	}
+	if fields["usage.messages[0].role"] != "user" {
+		t.Fatalf("usage.messages[0].role=%q", fields["usage.messages[0].role"])
+	}
+}
+
+func TestDecodeEmbeddedJSONMergesDecodedFields(t *testing.T) {
+	t.Parallel()
+
+	fields := map[string]string{
+		"arguments": `{"extension":"2765","retries":2}`,
+		"message":   "not json, left alone",
+	}
+	DecodeEmbeddedJSON(fields, FlattenOptions{})
+
+	if fields["arguments.extension"] != "2765" {
+		t.Fatalf("arguments.extension=%q", fields["arguments.extension"])
+	}
+	if fields["arguments.retries"] != "2" {
+		t.Fatalf("arguments.retries=%q", fields["arguments.retries"])
+	}
+	if fields["message"] != "not json, left alone" {
+		t.Fatalf("message=%q", fields["message"])
+	}
+}
+
+func TestFlattenJSONCapsDepthAndFieldCount(t *testing.T) {
+	t.Parallel()
+
+	fields, ok := FlattenJSON("a", `{"b":{"c":{"d":{"e":{"f":{"g":1}}}}}}`, FlattenOptions{MaxDepth: 2})
+	if !ok {
+		t.Fatalf("expected valid JSON to decode")
+	}
+	found := false
+	for _, v := range fields {
+		if v == "…(truncated, max depth reached)" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected a truncation marker, got %#v", fields)
+	}
+
+	wide, ok := FlattenJSON("a", `[1,2,3,4,5]`, FlattenOptions{MaxFields: 2})
+	if !ok {
+		t.Fatalf("expected valid JSON to decode")
+	}
+	if len(wide) != 2 {
+		t.Fatalf("expected field count capped at 2, got %d: %#v", len(wide), wide)
+	}
+}
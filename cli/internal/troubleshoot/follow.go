@@ -0,0 +1,488 @@
+package troubleshoot
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/troubleshoot/capture"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/troubleshoot/replay"
+)
+
+// FollowOptions configures `agent rca --follow`: which calls/lines to keep,
+// and where per-call reports go as they become available.
+type FollowOptions struct {
+	MinSeverity        string        // error/warn/info/debug; lines below this are not echoed live (default: info)
+	Tag                string        // only finalize calls whose buffered lines contain this substring
+	OnlySymptom        string        // only emit a report if this symptom's checker finds a root cause
+	ExcludeHealthy     bool          // skip emitting a report for calls that look healthy
+	IdleTimeout        time.Duration // finalize a call if no new lines arrive for this long (default 30s)
+	OutputFile         string        // optional NDJSON sink, one RCAReport per line
+	OutputFileMaxBytes int64         // rotate OutputFile once it exceeds this size (0 or negative means the 50MB default)
+
+	// Capture opts in to packet-capture cross-checking (--capture): tcpdump
+	// records the call's RTP/AudioSocket traffic once its ports are known
+	// from the RCA_CALL_START header, and the resulting pcap is analyzed and
+	// cross-checked against the log-derived metrics when the call ends.
+	Capture                     bool
+	CaptureDir                  string  // where to write .pcap files; default os.TempDir()
+	CaptureLossTolerancePercent float64 // default 1.0
+	CaptureJitterToleranceMs    float64 // default 20.0
+
+	// ReplayDir, if set, builds an HLS-style replay manifest (see
+	// troubleshoot/replay) from the call's --capture pcap once it ends,
+	// and records its URL on FormatAlignment.ManifestURL. No-op without
+	// Capture also set, since there's no pcap to build from otherwise.
+	ReplayDir string
+}
+
+func (o FollowOptions) withDefaults() FollowOptions {
+	if o.MinSeverity == "" {
+		o.MinSeverity = "info"
+	}
+	if o.IdleTimeout <= 0 {
+		o.IdleTimeout = 30 * time.Second
+	}
+	if o.OutputFile != "" && o.OutputFileMaxBytes <= 0 {
+		o.OutputFileMaxBytes = 50 * 1024 * 1024
+	}
+	if o.CaptureLossTolerancePercent <= 0 {
+		o.CaptureLossTolerancePercent = defaultCaptureLossTolerancePercent
+	}
+	if o.CaptureJitterToleranceMs <= 0 {
+		o.CaptureJitterToleranceMs = defaultCaptureJitterToleranceMs
+	}
+	return o
+}
+
+// followBufferLimit caps how many lines a single in-progress call keeps in
+// memory, so a stuck channel that never ends can't grow without bound.
+const followBufferLimit = 20000
+
+// callBuffer accumulates the log lines seen so far for one in-progress call.
+type callBuffer struct {
+	lines          []string
+	lastSeen       time.Time
+	captureSession *capture.Session // non-nil once --capture has seen this call's header and started tcpdump
+}
+
+func newCallBuffer() *callBuffer {
+	return &callBuffer{lastSeen: time.Now()}
+}
+
+func (b *callBuffer) append(line string) {
+	b.lines = append(b.lines, line)
+	if len(b.lines) > followBufferLimit {
+		b.lines = b.lines[len(b.lines)-followBufferLimit:]
+	}
+	b.lastSeen = time.Now()
+}
+
+// RunFollow tails ai_engine logs as they're written and, per call_id,
+// replays the same analysis pipeline Run uses for a one-shot report as soon
+// as the call ends (Stasis end / hangup) or goes idle for IdleTimeout —
+// instead of requiring a separate `agent rca --call <id>` after the fact.
+func (r *Runner) RunFollow() error {
+	LoadEnvFile()
+	opts := r.followOpts.withDefaults()
+
+	var out io.Writer = os.Stdout
+	if opts.OutputFile != "" {
+		rot, err := newRotatingFile(opts.OutputFile, opts.OutputFileMaxBytes)
+		if err != nil {
+			return fmt.Errorf("failed to open --output-file: %w", err)
+		}
+		defer rot.Close()
+		out = rot
+	}
+
+	lines, err := r.followLogLines(r.ctx)
+	if err != nil {
+		return fmt.Errorf("failed to follow logs: %w", err)
+	}
+
+	buffers := make(map[string]*callBuffer)
+	idleTicker := time.NewTicker(opts.IdleTimeout / 2)
+	defer idleTicker.Stop()
+
+	flush := func(callID string) {
+		buf, ok := buffers[callID]
+		if !ok {
+			return
+		}
+		delete(buffers, callID)
+		r.finalizeFollowedCall(callID, buf, opts, out)
+	}
+
+	for {
+		select {
+		case <-r.ctx.Done():
+			for id := range buffers {
+				flush(id)
+			}
+			return nil
+
+		case line, ok := <-lines:
+			if !ok {
+				for id := range buffers {
+					flush(id)
+				}
+				return nil
+			}
+
+			id := extractCallIDFromLine(line)
+			if id == "" {
+				continue
+			}
+			buf, ok := buffers[id]
+			if !ok {
+				buf = newCallBuffer()
+				buffers[id] = buf
+			}
+			buf.append(line)
+
+			if opts.Capture && buf.captureSession == nil {
+				if h := ExtractRCAHeader(line); h != nil && (h.ExternalMediaRTPPort > 0 || h.AudioSocketPort > 0) {
+					sess, err := capture.Start(r.ctx, id, capture.Options{
+						RTPPort:         h.ExternalMediaRTPPort,
+						AudioSocketPort: h.AudioSocketPort,
+						OutputDir:       opts.CaptureDir,
+					})
+					if err != nil {
+						if r.verbose {
+							fmt.Fprintf(os.Stderr, "[DEBUG] failed to start capture for call %s: %v\n", id, err)
+						}
+					} else {
+						buf.captureSession = sess
+					}
+				}
+			}
+
+			if !r.jsonOutput && opts.OutputFile == "" && matchesSeverity(line, opts.MinSeverity) {
+				fmt.Println(line)
+			}
+
+			if isCallEndLine(line) {
+				flush(id)
+			}
+
+		case <-idleTicker.C:
+			now := time.Now()
+			for id, buf := range buffers {
+				if now.Sub(buf.lastSeen) >= opts.IdleTimeout {
+					flush(id)
+				}
+			}
+		}
+	}
+}
+
+// followLogLines starts a streaming tail of ai_engine logs through the
+// configured LogSource (default: `docker logs -f`, matching the `docker
+// logs` call in collectCallData). RCA_LOG_FILE is still honored directly
+// here for backward compatibility with scripts that set it without also
+// setting RCA_LOG_SOURCE=file.
+func (r *Runner) followLogLines(ctx context.Context) (<-chan string, error) {
+	out := make(chan string, 1024)
+
+	if r.logSource == nil {
+		if logFile := os.Getenv("RCA_LOG_FILE"); logFile != "" {
+			f, err := os.Open(logFile)
+			if err != nil {
+				return nil, err
+			}
+			go followFile(ctx, f, out)
+			return out, nil
+		}
+	}
+
+	rc, err := r.logSourceOrDefault().Follow(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		defer close(out)
+		defer rc.Close()
+		ansiStripPattern := regexp.MustCompile(`\x1b\[[0-9;]*m`)
+		scanner := bufio.NewScanner(rc)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			out <- ansiStripPattern.ReplaceAllString(scanner.Text(), "")
+		}
+	}()
+
+	return out, nil
+}
+
+// followFile implements `tail -F` semantics for RCA_LOG_FILE: it reads
+// whatever is already in the file, then polls for appended lines.
+func followFile(ctx context.Context, f *os.File, out chan<- string) {
+	defer close(out)
+	defer f.Close()
+
+	reader := bufio.NewReader(f)
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			time.Sleep(500 * time.Millisecond)
+			continue
+		}
+		out <- strings.TrimRight(line, "\n")
+	}
+}
+
+// followCallIDPatterns mirror the call_id/channel_id patterns getRecentCalls
+// uses, but are applied one line at a time instead of in two passes over the
+// whole log. This means helper channel lines (AudioSocket/ExternalMedia
+// internal channels) get their own short-lived buffer instead of being
+// correlated back to the parent call the way collectCallData does — a
+// deliberate tradeoff for true streaming operation.
+var followCallIDPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`"call_id":\s*"([0-9]+\.[0-9]+)"`),
+	regexp.MustCompile(`(?:call_id|channel_id)[=:][\s]*"?([0-9]+\.[0-9]+)"?`),
+	regexp.MustCompile(`"caller_channel_id":\s*"([0-9]+\.[0-9]+)"`),
+	regexp.MustCompile(`caller_channel_id[=:][\s]*"?([0-9]+\.[0-9]+)"?`),
+}
+
+func extractCallIDFromLine(line string) string {
+	for _, re := range followCallIDPatterns {
+		if m := re.FindStringSubmatch(line); len(m) > 1 {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+// isCallEndLine reports whether a log line marks the end of a call (Stasis
+// end / hangup), so RunFollow can finalize a report as soon as it's
+// available instead of waiting for the idle timeout.
+func isCallEndLine(line string) bool {
+	l := strings.ToLower(line)
+	return strings.Contains(l, "stasisend") ||
+		strings.Contains(l, "channel left stasis") ||
+		strings.Contains(l, "channelhangup") ||
+		strings.Contains(l, "channel hangup") ||
+		strings.Contains(l, "call ended") ||
+		strings.Contains(l, "hangup request")
+}
+
+// lineSeverityRank classifies a single log line for --min-severity filtering.
+// There is no reliable debug marker shared by both log formats, so anything
+// that isn't recognizably error/warning/debug defaults to info.
+func lineSeverityRank(line string) int {
+	if isErrorLine(line) {
+		return 3
+	}
+	if isWarningLine(line) {
+		return 2
+	}
+	l := strings.ToLower(line)
+	if strings.Contains(l, "\"level\":\"debug\"") || strings.Contains(l, " level=debug") || strings.Contains(l, "[debug") {
+		return 0
+	}
+	return 1
+}
+
+func severityRank(name string) int {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "error":
+		return 3
+	case "warn", "warning":
+		return 2
+	case "debug":
+		return 0
+	default:
+		return 1
+	}
+}
+
+func matchesSeverity(line, minSeverity string) bool {
+	return lineSeverityRank(line) >= severityRank(minSeverity)
+}
+
+// finalizeFollowedCall runs the same analysis pipeline Run uses for a
+// one-shot report against a single call's buffered lines, applies the
+// --tag/--only-symptom/--exclude-healthy filters, and writes the result to
+// out (an NDJSON RCAReport when jsonOutput or --output-file is set, a human
+// panel on stdout otherwise).
+func (r *Runner) finalizeFollowedCall(callID string, buf *callBuffer, opts FollowOptions, out io.Writer) {
+	logData := strings.Join(buf.lines, "\n")
+
+	if opts.Tag != "" && !strings.Contains(logData, opts.Tag) {
+		return
+	}
+
+	sub := &Runner{verbose: r.verbose, ctx: r.ctx, callID: callID, symptom: opts.OnlySymptom, jsonOutput: r.jsonOutput, explain: r.explain}
+	analysis := sub.analyzeBasic(logData)
+	analysis.Header = ExtractRCAHeader(logData)
+	analysis.ProviderRuntime = ExtractProviderRuntimeAudio(logData)
+	if (analysis.AudioTransport == "" || strings.ToLower(strings.TrimSpace(analysis.AudioTransport)) == "unknown") && analysis.Header != nil && analysis.Header.AudioTransport != "" {
+		analysis.AudioTransport = strings.ToLower(strings.TrimSpace(analysis.Header.AudioTransport))
+	}
+
+	metrics := ExtractMetrics(logData)
+	analysis.Metrics = metrics
+	metrics.FormatAlignment = AnalyzeFormatAlignment(metrics, analysis.Header, logData)
+	AnalyzeLoudness(metrics, logData, analysis.ProviderRuntime)
+
+	var pcm *capture.PacketCaptureMetrics
+	if buf.captureSession != nil {
+		if err := buf.captureSession.Stop(); err != nil && r.verbose {
+			fmt.Fprintf(os.Stderr, "[DEBUG] capture stop failed for call %s: %v\n", callID, err)
+		}
+		rtpPort := 0
+		if analysis.Header != nil {
+			rtpPort = analysis.Header.ExternalMediaRTPPort
+		}
+		m, err := capture.Analyze(buf.captureSession.PcapPath(), rtpPort)
+		if err != nil {
+			if r.verbose {
+				fmt.Fprintf(os.Stderr, "[DEBUG] pcap analysis failed for call %s: %v\n", callID, err)
+			}
+		} else {
+			pcm = m
+			analysis.Warnings = append(analysis.Warnings, CrossCheckCapture(metrics, pcm, opts.CaptureLossTolerancePercent, opts.CaptureJitterToleranceMs)...)
+
+			if opts.ReplayDir != "" {
+				manifest, err := replay.GenerateManifest(buf.captureSession.PcapPath(), replay.Options{
+					CallID:    callID,
+					OutputDir: opts.ReplayDir,
+					RTPPort:   rtpPort,
+				}, nil)
+				if err != nil {
+					if r.verbose {
+						fmt.Fprintf(os.Stderr, "[DEBUG] replay manifest failed for call %s: %v\n", callID, err)
+					}
+				} else if metrics.FormatAlignment != nil {
+					metrics.FormatAlignment.ManifestURL = manifest.URL
+				}
+			}
+		}
+	}
+
+	if baselineName := detectBaseline(logData); baselineName != "" {
+		analysis.BaselineComparison = CompareToBaseline(metrics, baselineName)
+	}
+
+	if opts.OnlySymptom != "" {
+		checker := NewSymptomChecker(opts.OnlySymptom)
+		checker.AnalyzeSymptom(analysis, logData)
+		if analysis.SymptomAnalysis == nil || len(analysis.SymptomAnalysis.RootCauses) == 0 {
+			return
+		}
+	}
+
+	if opts.ExcludeHealthy {
+		score, issues := evaluateCallQuality(metrics)
+		if score >= 90 && len(issues) == 0 && len(analysis.Errors) == 0 {
+			return
+		}
+	}
+
+	var llmDiagnosis *LLMDiagnosis
+	if !r.noLLM && (r.forceLLM || shouldRunLLM(analysis, metrics, logData)) {
+		if analyzer, err := NewAnalyzer(); err == nil {
+			if r.explain {
+				llmDiagnosis, _ = NewExplainer(analyzer).Explain(r.ctx, analysis, logData)
+			} else {
+				llmDiagnosis, _ = analyzer.Analyze(r.ctx, analysis, logData)
+			}
+		}
+	}
+
+	if r.jsonOutput || opts.OutputFile != "" {
+		enc := json.NewEncoder(out)
+		rep := buildRCAReport(analysis, llmDiagnosis)
+		rep.PacketCaptureMetrics = pcm
+		_ = enc.Encode(rep)
+		return
+	}
+
+	fmt.Printf("\n━━━ Call %s finalized ━━━\n", callID)
+	sub.displayHeader(analysis.Header, analysis.ProviderRuntime)
+	sub.displayFindings(analysis)
+	if analysis.Metrics != nil {
+		sub.displayMetrics(analysis.Metrics)
+		sub.displayCallQuality(analysis)
+	}
+	if pcm != nil {
+		fmt.Printf("  📡 Packet capture: %d RTP packets, jitter=%.2fms, loss=%.2f%%, bitrate=%.1fkbps (%s)\n",
+			pcm.RTPPacketCount, pcm.JitterMs, pcm.LossPercent, pcm.BitrateKbps, pcm.PcapPath)
+	}
+	if llmDiagnosis != nil {
+		if sub.explain {
+			sub.displayExplanation(llmDiagnosis)
+		} else {
+			sub.displayLLMDiagnosis(llmDiagnosis)
+		}
+	}
+}
+
+// rotatingFile is an io.WriteCloser that rotates to a single ".1" backup
+// once the primary file exceeds maxBytes, so a long --follow capture session
+// with --output-file doesn't fill disk.
+type rotatingFile struct {
+	path     string
+	maxBytes int64
+	f        *os.File
+	written  int64
+}
+
+func newRotatingFile(path string, maxBytes int64) (*rotatingFile, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &rotatingFile{path: path, maxBytes: maxBytes, f: f, written: info.Size()}, nil
+}
+
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	if r.maxBytes > 0 && r.written+int64(len(p)) > r.maxBytes {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.f.Write(p)
+	r.written += int64(n)
+	return n, err
+}
+
+func (r *rotatingFile) rotate() error {
+	if err := r.f.Close(); err != nil {
+		return err
+	}
+	backup := r.path + ".1"
+	_ = os.Remove(backup)
+	if err := os.Rename(r.path, backup); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(r.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	r.f = f
+	r.written = 0
+	return nil
+}
+
+func (r *rotatingFile) Close() error {
+	return r.f.Close()
+}
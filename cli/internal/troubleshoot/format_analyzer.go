@@ -9,7 +9,7 @@ import (
 //
 // IMPORTANT: RCA is log-driven. We do not shell out to read config files here.
 // Config-side values should come from an explicit log header (RCA_CALL_START).
-func AnalyzeFormatAlignment(metrics *CallMetrics, header *RCAHeader) *FormatAlignment {
+func AnalyzeFormatAlignment(metrics *CallMetrics, header *RCAHeader, logData string) *FormatAlignment {
 	alignment := &FormatAlignment{
 		Issues: []string{},
 	}
@@ -19,6 +19,9 @@ func AnalyzeFormatAlignment(metrics *CallMetrics, header *RCAHeader) *FormatAlig
 		alignment.ConfigAudioTransport = strings.ToLower(strings.TrimSpace(header.AudioTransport))
 		alignment.ConfigAudioSocketFormat = strings.TrimSpace(header.AudioSocketFormat)
 		alignment.ConfigSampleRate = header.StreamingSampleRate
+		alignment.ProfileName = strings.TrimSpace(header.ProfileName)
+		alignment.ProfileChannels = header.ProfileChannels
+		alignment.ProfileBitDepth = header.ProfileBitDepth
 		if header.ProviderInputEncoding != "" {
 			alignment.ConfigProviderInputFormat = header.ProviderInputEncoding
 		} else if header.ProviderProviderInputEncoding != "" {
@@ -34,23 +37,128 @@ func AnalyzeFormatAlignment(metrics *CallMetrics, header *RCAHeader) *FormatAlig
 	alignment.RuntimeProviderInputFormat = metrics.ProviderInputFormat
 	alignment.RuntimeSampleRate = metrics.SampleRate
 
+	// Look up the declarative golden baseline for this call's
+	// (pipeline, transport), replacing the old hardcoded slin/320-byte
+	// assumptions (see format_profile.go).
+	pipelineName := ""
+	if header != nil {
+		pipelineName = header.PipelineName
+	}
+	profile, hasProfile := findFormatProfile(pipelineName, alignment.ConfigAudioTransport)
+
 	// Analyze frame sizes
-	analyzeFrameSizes(alignment, metrics)
+	analyzeFrameSizes(alignment, metrics, profile, hasProfile)
 
 	// Detect misalignments
-	detectMisalignments(alignment)
+	detectMisalignments(alignment, profile, hasProfile)
+
+	// Validate the externalmedia RTP path end-to-end, if that's the
+	// transport this call used.
+	validateExternalMediaCodec(alignment, header, metrics)
+
+	// Cross-check against nbtrace's ring-derived frame cadence, if logged
+	crossCheckTraceCadence(alignment, extractTraceSummary(logData))
 
 	return alignment
 }
 
-func analyzeFrameSizes(alignment *FormatAlignment, metrics *CallMetrics) {
-	// Calculate expected frame size based on format
-	if alignment.RuntimeAudioSocketFormat == "slin" || alignment.RuntimeAudioSocketFormat == "slin16" {
-		// PCM16 @ 8kHz, 20ms frame = 320 bytes
-		alignment.ExpectedFrameSize = 320
-	} else if alignment.RuntimeAudioSocketFormat == "ulaw" || alignment.RuntimeAudioSocketFormat == "mulaw" {
-		// μ-law @ 8kHz, 20ms frame = 160 bytes
-		alignment.ExpectedFrameSize = 160
+// externalMediaCodecSpec describes the expected RTP payload shape for one
+// externalmedia codec, so validateExternalMediaCodec can compute an
+// expected bytes-per-packet figure generically from the codec's sample
+// rate, sample width and channel count.
+type externalMediaCodecSpec struct {
+	SampleRateHz   int
+	BytesPerSample int // 0 means "framed codec, payload size isn't a fixed function of ptime"
+	Channels       int
+}
+
+// externalMediaCodecSpecs covers the codecs ai_engine's externalmedia path
+// is documented to negotiate: G.711 (8kHz companded, 1 byte/sample) and
+// L16 LPCM at the telephony/wideband/super-wideband rates ai_engine
+// supports, each 2 bytes/sample big-endian. MP4A-LATM/Opus are framed
+// (variable payload size per ptime) so they're listed with
+// BytesPerSample=0 to skip the payload-size check while still validating
+// the RTP clock.
+var externalMediaCodecSpecs = map[string]externalMediaCodecSpec{
+	"ulaw":      {SampleRateHz: 8000, BytesPerSample: 1, Channels: 1},
+	"mulaw":     {SampleRateHz: 8000, BytesPerSample: 1, Channels: 1},
+	"pcmu":      {SampleRateHz: 8000, BytesPerSample: 1, Channels: 1},
+	"alaw":      {SampleRateHz: 8000, BytesPerSample: 1, Channels: 1},
+	"pcma":      {SampleRateHz: 8000, BytesPerSample: 1, Channels: 1},
+	"l16-8000":  {SampleRateHz: 8000, BytesPerSample: 2, Channels: 1},
+	"l16-16000": {SampleRateHz: 16000, BytesPerSample: 2, Channels: 1},
+	"l16-24000": {SampleRateHz: 24000, BytesPerSample: 2, Channels: 1},
+	"opus":      {SampleRateHz: 48000, BytesPerSample: 0, Channels: 1},
+	"mp4a-latm": {SampleRateHz: 44100, BytesPerSample: 0, Channels: 1},
+}
+
+// validateExternalMediaCodec cross-checks RCAHeader.ExternalMediaCodec
+// against runtime RTP observations when this call used the externalmedia
+// transport. AudioSocket calls are already covered by analyzeFrameSizes/
+// detectMisalignments above, so this only fires for externalmedia.
+func validateExternalMediaCodec(alignment *FormatAlignment, header *RCAHeader, metrics *CallMetrics) {
+	if header == nil || strings.ToLower(strings.TrimSpace(header.AudioTransport)) != "externalmedia" {
+		return
+	}
+
+	codec := strings.ToLower(strings.TrimSpace(header.ExternalMediaCodec))
+	spec, known := externalMediaCodecSpecs[codec]
+	if !known {
+		return
+	}
+
+	if spec.BytesPerSample > 0 && metrics.ExternalMediaObservedPayloadBytes > 0 {
+		samplesPerPtime := spec.SampleRateHz * defaultPtimeMs / 1000
+		expected := samplesPerPtime * spec.BytesPerSample * spec.Channels
+		if expected != metrics.ExternalMediaObservedPayloadBytes {
+			msg := fmt.Sprintf(
+				"externalmedia codec %s declared (expected %d bytes/packet @ %dms ptime), observed %d bytes/packet",
+				header.ExternalMediaCodec, expected, defaultPtimeMs, metrics.ExternalMediaObservedPayloadBytes)
+			alignment.Issues = append(alignment.Issues, msg)
+			alignment.ExternalMediaCodedIssues = append(alignment.ExternalMediaCodedIssues, FormatIssue{
+				Code: IssueExternalMediaPayloadSizeMismatch, Message: msg,
+			})
+		}
+	}
+
+	if metrics.ExternalMediaObservedRTPClockHz > 0 && alignment.ConfigSampleRate > 0 &&
+		metrics.ExternalMediaObservedRTPClockHz != alignment.ConfigSampleRate {
+		msg := fmt.Sprintf(
+			"externalmedia RTP timestamp clock is %dHz but StreamingSampleRate is %dHz",
+			metrics.ExternalMediaObservedRTPClockHz, alignment.ConfigSampleRate)
+		alignment.Issues = append(alignment.Issues, msg)
+		alignment.ExternalMediaCodedIssues = append(alignment.ExternalMediaCodedIssues, FormatIssue{
+			Code: IssueExternalMediaClockMismatch, Message: msg,
+		})
+	}
+
+	advertiseHost := strings.TrimSpace(header.ExternalMediaAdvertiseHost)
+	sdpHost := strings.TrimSpace(metrics.ExternalMediaSDPConnectionHost)
+	if advertiseHost != "" && sdpHost != "" && advertiseHost != sdpHost {
+		msg := fmt.Sprintf(
+			"ExternalMediaAdvertiseHost=%s does not match SDP c= line host %s", advertiseHost, sdpHost)
+		alignment.Issues = append(alignment.Issues, msg)
+		alignment.ExternalMediaCodedIssues = append(alignment.ExternalMediaCodedIssues, FormatIssue{
+			Code: IssueExternalMediaHostMismatch, Message: msg,
+		})
+	}
+}
+
+// defaultPtimeMs is the frame duration assumed for telephony audio
+// (Asterisk's standard 20ms packetization interval).
+const defaultPtimeMs = 20
+
+func analyzeFrameSizes(alignment *FormatAlignment, metrics *CallMetrics, profile FormatProfile, hasProfile bool) {
+	// Prefer the per-call wizard.PipelineProfile round-tripped through the
+	// RCA header (sample rate, channels, bit depth actually selected for
+	// this call) over the declarative FormatProfile golden baseline below.
+	switch {
+	case alignment.ProfileChannels > 0 && alignment.ProfileBitDepth > 0 && alignment.ConfigSampleRate > 0:
+		bytesPerSample := alignment.ProfileBitDepth / 8
+		samplesPerFrame := alignment.ConfigSampleRate * defaultPtimeMs / 1000
+		alignment.ExpectedFrameSize = samplesPerFrame * alignment.ProfileChannels * bytesPerSample
+	case hasProfile:
+		alignment.ExpectedFrameSize = profile.ExpectedFrameBytes()
 	}
 
 	// Observe actual frame sizes from provider bytes
@@ -60,16 +168,20 @@ func analyzeFrameSizes(alignment *FormatAlignment, metrics *CallMetrics) {
 	}
 }
 
-func detectMisalignments(alignment *FormatAlignment) {
+func detectMisalignments(alignment *FormatAlignment, profile FormatProfile, hasProfile bool) {
 	transport := strings.ToLower(strings.TrimSpace(alignment.ConfigAudioTransport))
+	profileSuffix := ""
+	if alignment.ProfileName != "" {
+		profileSuffix = fmt.Sprintf(" (profile=%s)", alignment.ProfileName)
+	}
 
 	// Check AudioSocket format mismatch
 	if transport == "audiosocket" && alignment.ConfigAudioSocketFormat != "" && alignment.RuntimeAudioSocketFormat != "" {
 		if alignment.ConfigAudioSocketFormat != alignment.RuntimeAudioSocketFormat {
 			alignment.AudioSocketMismatch = true
 			alignment.Issues = append(alignment.Issues, fmt.Sprintf(
-				"AudioSocket format mismatch: config=%s, runtime=%s",
-				alignment.ConfigAudioSocketFormat, alignment.RuntimeAudioSocketFormat))
+				"AudioSocket format mismatch: config=%s, runtime=%s%s",
+				alignment.ConfigAudioSocketFormat, alignment.RuntimeAudioSocketFormat, profileSuffix))
 		}
 	}
 
@@ -85,12 +197,14 @@ func detectMisalignments(alignment *FormatAlignment) {
 		}
 	}
 
-	// Check AudioSocket format is correct (golden baseline)
-	if transport == "audiosocket" && alignment.RuntimeAudioSocketFormat != "" && alignment.RuntimeAudioSocketFormat != "slin" {
+	// Check the runtime encoding against this (pipeline, transport)'s
+	// declarative golden baseline (see format_profile.go).
+	if hasProfile && profile.Encoding != "" && alignment.RuntimeAudioSocketFormat != "" &&
+		normalizeFormat(alignment.RuntimeAudioSocketFormat) != normalizeFormat(profile.Encoding) {
 		alignment.AudioSocketMismatch = true
 		alignment.Issues = append(alignment.Issues, fmt.Sprintf(
-			"AudioSocket format should be 'slin' (golden baseline), got '%s'",
-			alignment.RuntimeAudioSocketFormat))
+			"%s format should be '%s' (golden baseline), got '%s'%s",
+			transport, profile.Encoding, alignment.RuntimeAudioSocketFormat, profileSuffix))
 	}
 
 	// Check frame size alignment
@@ -104,8 +218,8 @@ func detectMisalignments(alignment *FormatAlignment) {
 		if diff > tolerance {
 			alignment.FrameSizeMismatch = true
 			alignment.Issues = append(alignment.Issues, fmt.Sprintf(
-				"Frame size mismatch: expected ~%d bytes, observed ~%d bytes",
-				alignment.ExpectedFrameSize, alignment.ObservedFrameSize))
+				"Frame size mismatch: expected ~%d bytes, observed ~%d bytes%s",
+				alignment.ExpectedFrameSize, alignment.ObservedFrameSize, profileSuffix))
 		}
 	}
 }
@@ -167,5 +281,9 @@ func (fa *FormatAlignment) FormatForLLM() string {
 		}
 	}
 
+	if fa.ManifestURL != "" {
+		out.WriteString(fmt.Sprintf("\nReplay manifest: %s\n", fa.ManifestURL))
+	}
+
 	return out.String()
 }
@@ -0,0 +1,130 @@
+package troubleshoot
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FormatProfile declares the expected audio shape for one (pipeline,
+// transport) combination. It replaces the hardcoded "AudioSocket format
+// should be 'slin'" golden baseline and the inline 320/160 frame-size
+// branch that used to live directly in analyzeFrameSizes/
+// detectMisalignments: operators add new pipelines (e.g. Opus 48k mono,
+// L16 16k stereo) via profiles.yaml instead of patching Go.
+type FormatProfile struct {
+	PipelineName      string   `yaml:"pipeline_name"`
+	AudioTransport    string   `yaml:"audio_transport"`
+	Encoding          string   `yaml:"encoding"`
+	SampleRateHz      int      `yaml:"sample_rate_hz"`
+	Channels          int      `yaml:"channels"`
+	BitDepth          int      `yaml:"bit_depth"`
+	PtimeMs           int      `yaml:"ptime_ms"`
+	ProviderEncodings []string `yaml:"provider_encodings"`
+}
+
+// ExpectedFrameBytes returns this profile's expected payload size per
+// ptime frame, or 0 if the profile doesn't declare enough to compute one.
+func (p FormatProfile) ExpectedFrameBytes() int {
+	if p.SampleRateHz <= 0 || p.BitDepth <= 0 || p.Channels <= 0 || p.PtimeMs <= 0 {
+		return 0
+	}
+	samplesPerFrame := p.SampleRateHz * p.PtimeMs / 1000
+	return samplesPerFrame * p.Channels * (p.BitDepth / 8)
+}
+
+// formatProfileKey is the registry key: "<pipelineName>|<audioTransport>".
+// "*" is the wildcard pipeline name used for transport-wide defaults.
+func formatProfileKey(pipelineName, audioTransport string) string {
+	return strings.ToLower(strings.TrimSpace(pipelineName)) + "|" + strings.ToLower(strings.TrimSpace(audioTransport))
+}
+
+// defaultFormatProfiles seeds the registry with the golden baselines that
+// used to be hardcoded, so deployments with no profiles.yaml keep
+// behaving the same.
+func defaultFormatProfiles() map[string]FormatProfile {
+	return map[string]FormatProfile{
+		formatProfileKey("*", "audiosocket"): {
+			AudioTransport: "audiosocket", Encoding: "slin",
+			SampleRateHz: 8000, Channels: 1, BitDepth: 16, PtimeMs: defaultPtimeMs,
+		},
+		formatProfileKey("*", "externalmedia"): {
+			AudioTransport: "externalmedia", Encoding: "ulaw",
+			SampleRateHz: 8000, Channels: 1, BitDepth: 8, PtimeMs: defaultPtimeMs,
+		},
+	}
+}
+
+// formatProfileRegistry is the active table AnalyzeFormatAlignment looks up.
+// It starts out as defaultFormatProfiles and is swapped wholesale by
+// SetFormatProfiles once a profiles.yaml has been loaded.
+var formatProfileRegistry = defaultFormatProfiles()
+
+// SetFormatProfiles replaces the active registry, e.g. after
+// LoadFormatProfiles, or from `agent rca serve`'s GET /rca/profiles setup.
+func SetFormatProfiles(registry map[string]FormatProfile) {
+	if len(registry) == 0 {
+		return
+	}
+	formatProfileRegistry = registry
+}
+
+// FormatProfiles returns the currently active registry, for GET
+// /rca/profiles and similar read-only consumers.
+func FormatProfiles() map[string]FormatProfile {
+	return formatProfileRegistry
+}
+
+// LoadFormatProfiles reads a profiles.yaml shaped as a top-level
+// "profiles:" list, each entry matching FormatProfile's yaml tags.
+func LoadFormatProfiles(path string) (map[string]FormatProfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var doc struct {
+		Profiles []FormatProfile `yaml:"profiles"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	registry := make(map[string]FormatProfile, len(doc.Profiles))
+	for _, p := range doc.Profiles {
+		pipelineName := p.PipelineName
+		if pipelineName == "" {
+			pipelineName = "*"
+		}
+		registry[formatProfileKey(pipelineName, p.AudioTransport)] = p
+	}
+	return registry, nil
+}
+
+// ProfilesHTTPHandler serves the active registry as JSON for GET
+// /rca/profiles, so operators can confirm what golden baseline a given
+// pipeline/transport combo will be checked against without reading Go.
+func ProfilesHTTPHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(formatProfileRegistry)
+}
+
+// findFormatProfile looks up pipelineName's profile for audioTransport,
+// falling back to the transport's wildcard ("*") entry.
+func findFormatProfile(pipelineName, audioTransport string) (FormatProfile, bool) {
+	if p, ok := formatProfileRegistry[formatProfileKey(pipelineName, audioTransport)]; ok {
+		return p, true
+	}
+	if p, ok := formatProfileRegistry[formatProfileKey("*", audioTransport)]; ok {
+		return p, true
+	}
+	return FormatProfile{}, false
+}
@@ -0,0 +1,283 @@
+package troubleshoot
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FloatRange is an inclusive [Min, Max] expected range for a GoldenBaseline
+// field.
+type FloatRange struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+}
+
+func (r FloatRange) contains(v float64) bool { return v >= r.Min && v <= r.Max }
+
+// GoldenBaseline is a known-good reference for one provider/transport
+// combo, loaded by LoadGoldenBaseline for `agent rca --diff-baseline` and
+// written by WriteGoldenBaseline for `agent rca --write-baseline`. A nil
+// range or empty string means that field isn't checked.
+type GoldenBaseline struct {
+	Name                    string      `json:"name"`
+	ProviderBytesRatio      *FloatRange `json:"provider_bytes_ratio,omitempty"`
+	WorstDriftPct           *FloatRange `json:"worst_drift_pct,omitempty"`
+	UnderflowRate           *FloatRange `json:"underflow_rate,omitempty"`
+	VADWebRTCAggressiveness *int        `json:"vad_webrtc_aggressiveness,omitempty"`
+	AudioSocketFormat       string      `json:"audiosocket_format,omitempty"`
+	ProviderInputFormat     string      `json:"provider_input_format,omitempty"`
+	ProviderOutputFormat    string      `json:"provider_output_format,omitempty"`
+	SampleRate              int         `json:"sample_rate,omitempty"`
+}
+
+// defaultGoldenBaselines are built-in fallbacks for the names detectBaseline
+// already recognizes, used when no baselines/<name>.json file overrides
+// them. Intentionally loose — teams should --write-baseline from their own
+// known-good call per provider rather than rely on these defaults.
+func defaultGoldenBaselines() map[string]GoldenBaseline {
+	aggr1 := 1
+	return map[string]GoldenBaseline{
+		"openai_realtime": {
+			Name:                    "openai_realtime",
+			ProviderBytesRatio:      &FloatRange{Min: 0.95, Max: 1.05},
+			WorstDriftPct:           &FloatRange{Min: -10, Max: 10},
+			UnderflowRate:           &FloatRange{Min: 0, Max: 1},
+			VADWebRTCAggressiveness: &aggr1,
+			SampleRate:              24000,
+		},
+		"deepgram_standard": {
+			Name:                    "deepgram_standard",
+			ProviderBytesRatio:      &FloatRange{Min: 0.95, Max: 1.05},
+			WorstDriftPct:           &FloatRange{Min: -10, Max: 10},
+			UnderflowRate:           &FloatRange{Min: 0, Max: 1},
+			VADWebRTCAggressiveness: &aggr1,
+			SampleRate:              16000,
+		},
+		"streaming_performance": {
+			Name:               "streaming_performance",
+			ProviderBytesRatio: &FloatRange{Min: 0.95, Max: 1.05},
+			WorstDriftPct:      &FloatRange{Min: -10, Max: 10},
+			UnderflowRate:      &FloatRange{Min: 0, Max: 1},
+		},
+	}
+}
+
+// baselinesDir resolves where user-overridable baseline JSON files live:
+// $RCA_BASELINES_DIR if set, else ./baselines.
+func baselinesDir() string {
+	if dir := os.Getenv("RCA_BASELINES_DIR"); dir != "" {
+		return dir
+	}
+	return "baselines"
+}
+
+// LoadGoldenBaseline loads baselines/<name>.json if present, else falls
+// back to the built-in default for that name.
+func LoadGoldenBaseline(name string) (*GoldenBaseline, error) {
+	path := filepath.Join(baselinesDir(), name+".json")
+	if data, err := os.ReadFile(path); err == nil {
+		var b GoldenBaseline
+		if err := json.Unmarshal(data, &b); err != nil {
+			return nil, fmt.Errorf("failed to parse baseline file %s: %w", path, err)
+		}
+		if b.Name == "" {
+			b.Name = name
+		}
+		return &b, nil
+	}
+
+	if b, ok := defaultGoldenBaselines()[name]; ok {
+		return &b, nil
+	}
+	return nil, fmt.Errorf("no baseline named %q (looked for %s and the built-in defaults)", name, path)
+}
+
+// WriteGoldenBaseline snapshots metrics into a GoldenBaseline JSON file at
+// path, for `agent rca --write-baseline=<path>` to capture a known-good
+// call. Ranges are padded a little around the observed value rather than
+// pinned exactly to it, since a single call is a sample, not a spec.
+func WriteGoldenBaseline(path, name string, metrics *CallMetrics) error {
+	underflowRate := 0.0
+	if metrics.UnderflowCount > 0 && len(metrics.StreamingSummaries) > 0 {
+		totalFrames := 0
+		for _, seg := range metrics.StreamingSummaries {
+			totalFrames += seg.BytesSent / 320
+		}
+		if totalFrames > 0 {
+			underflowRate = float64(metrics.UnderflowCount) / float64(totalFrames) * 100
+		}
+	}
+	ratio := 1.0
+	if len(metrics.ProviderSegments) > 0 && metrics.ProviderBytesTotal > 0 {
+		ratio = float64(metrics.EnqueuedBytesTotal) / float64(metrics.ProviderBytesTotal)
+	}
+
+	b := GoldenBaseline{
+		Name:                 name,
+		ProviderBytesRatio:   &FloatRange{Min: ratio - 0.05, Max: ratio + 0.05},
+		WorstDriftPct:        &FloatRange{Min: -abs(metrics.WorstDriftPct) - 2, Max: abs(metrics.WorstDriftPct) + 2},
+		UnderflowRate:        &FloatRange{Min: 0, Max: underflowRate + 1},
+		AudioSocketFormat:    metrics.AudioSocketFormat,
+		ProviderInputFormat:  metrics.ProviderInputFormat,
+		ProviderOutputFormat: metrics.ProviderOutputFormat,
+		SampleRate:           metrics.SampleRate,
+	}
+	if metrics.VADSettings != nil {
+		aggr := metrics.VADSettings.WebRTCAggressiveness
+		b.VADWebRTCAggressiveness = &aggr
+	}
+
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(path); dir != "." && dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// BaselineCheck is one row of a --diff-baseline report: an expected value
+// or range compared against what the call actually did.
+type BaselineCheck struct {
+	Name     string `json:"name"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+	Pass     bool   `json:"pass"`
+}
+
+// DiffBaseline compares metrics against baseline and returns one
+// BaselineCheck per field the baseline constrains (fields left unset in
+// the baseline are skipped rather than reported as a pass).
+func DiffBaseline(metrics *CallMetrics, baseline *GoldenBaseline) []BaselineCheck {
+	var checks []BaselineCheck
+
+	if baseline.ProviderBytesRatio != nil {
+		ratio := 0.0
+		if len(metrics.ProviderSegments) > 0 && metrics.ProviderBytesTotal > 0 {
+			ratio = float64(metrics.EnqueuedBytesTotal) / float64(metrics.ProviderBytesTotal)
+		}
+		checks = append(checks, BaselineCheck{
+			Name:     "ProviderBytesRatio",
+			Expected: fmt.Sprintf("%.2f-%.2f", baseline.ProviderBytesRatio.Min, baseline.ProviderBytesRatio.Max),
+			Actual:   fmt.Sprintf("%.2f", ratio),
+			Pass:     baseline.ProviderBytesRatio.contains(ratio),
+		})
+	}
+
+	if baseline.WorstDriftPct != nil {
+		checks = append(checks, BaselineCheck{
+			Name:     "WorstDriftPct",
+			Expected: fmt.Sprintf("%.1f%% to %.1f%%", baseline.WorstDriftPct.Min, baseline.WorstDriftPct.Max),
+			Actual:   fmt.Sprintf("%.1f%%", metrics.WorstDriftPct),
+			Pass:     baseline.WorstDriftPct.contains(metrics.WorstDriftPct),
+		})
+	}
+
+	if baseline.UnderflowRate != nil {
+		rate := 0.0
+		if metrics.UnderflowCount > 0 && len(metrics.StreamingSummaries) > 0 {
+			totalFrames := 0
+			for _, seg := range metrics.StreamingSummaries {
+				totalFrames += seg.BytesSent / 320
+			}
+			if totalFrames > 0 {
+				rate = float64(metrics.UnderflowCount) / float64(totalFrames) * 100
+			}
+		}
+		checks = append(checks, BaselineCheck{
+			Name:     "UnderflowRate",
+			Expected: fmt.Sprintf("%.1f%% to %.1f%%", baseline.UnderflowRate.Min, baseline.UnderflowRate.Max),
+			Actual:   fmt.Sprintf("%.1f%%", rate),
+			Pass:     baseline.UnderflowRate.contains(rate),
+		})
+	}
+
+	if baseline.VADWebRTCAggressiveness != nil {
+		actual := -1
+		if metrics.VADSettings != nil {
+			actual = metrics.VADSettings.WebRTCAggressiveness
+		}
+		checks = append(checks, BaselineCheck{
+			Name:     "VADSettings.WebRTCAggressiveness",
+			Expected: fmt.Sprintf("%d", *baseline.VADWebRTCAggressiveness),
+			Actual:   fmt.Sprintf("%d", actual),
+			Pass:     actual == *baseline.VADWebRTCAggressiveness,
+		})
+	}
+
+	if baseline.AudioSocketFormat != "" {
+		checks = append(checks, BaselineCheck{
+			Name:     "AudioSocketFormat",
+			Expected: baseline.AudioSocketFormat,
+			Actual:   metrics.AudioSocketFormat,
+			Pass:     strings.EqualFold(metrics.AudioSocketFormat, baseline.AudioSocketFormat),
+		})
+	}
+
+	if baseline.ProviderInputFormat != "" {
+		checks = append(checks, BaselineCheck{
+			Name:     "ProviderInputFormat",
+			Expected: baseline.ProviderInputFormat,
+			Actual:   metrics.ProviderInputFormat,
+			Pass:     strings.EqualFold(metrics.ProviderInputFormat, baseline.ProviderInputFormat),
+		})
+	}
+
+	if baseline.ProviderOutputFormat != "" {
+		checks = append(checks, BaselineCheck{
+			Name:     "ProviderOutputFormat",
+			Expected: baseline.ProviderOutputFormat,
+			Actual:   metrics.ProviderOutputFormat,
+			Pass:     strings.EqualFold(metrics.ProviderOutputFormat, baseline.ProviderOutputFormat),
+		})
+	}
+
+	if baseline.SampleRate > 0 {
+		checks = append(checks, BaselineCheck{
+			Name:     "SampleRate",
+			Expected: fmt.Sprintf("%d Hz", baseline.SampleRate),
+			Actual:   fmt.Sprintf("%d Hz", metrics.SampleRate),
+			Pass:     metrics.SampleRate == baseline.SampleRate,
+		})
+	}
+
+	return checks
+}
+
+// displayBaselineDiff renders --diff-baseline's checks as a colored table.
+func (r *Runner) displayBaselineDiff(baselineName string, checks []BaselineCheck) {
+	fmt.Println("═══════════════════════════════════════════")
+	fmt.Printf("📐 BASELINE DIFF: %s\n", baselineName)
+	fmt.Println("═══════════════════════════════════════════")
+	fmt.Println()
+
+	if len(checks) == 0 {
+		warningColor.Println("No fields in this baseline apply to this call's metrics")
+		fmt.Println()
+		return
+	}
+
+	failed := 0
+	for _, c := range checks {
+		line := fmt.Sprintf("  %-32s expected %-20s actual %s", c.Name, c.Expected, c.Actual)
+		if c.Pass {
+			successColor.Printf("%s ✅ PASS\n", line)
+		} else {
+			errorColor.Printf("%s ❌ FAIL\n", line)
+			failed++
+		}
+	}
+	fmt.Println()
+	if failed > 0 {
+		errorColor.Printf("%d/%d checks failed\n", failed, len(checks))
+	} else {
+		successColor.Printf("All %d checks passed\n", len(checks))
+	}
+	fmt.Println()
+}
@@ -0,0 +1,117 @@
+package troubleshoot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/encoding"
+)
+
+// grpcDiagnoseMethod is the fully-qualified RPC name from proto/analyzer.proto.
+const grpcDiagnoseMethod = "/troubleshoot.AnalyzerService/Diagnose"
+
+func init() {
+	// AnalyzerService exchanges plain JSON rather than wire-format protobuf,
+	// so a third-party backend can be a small Python/Node/etc. process that
+	// speaks gRPC+JSON without a generated protobuf client. The .proto file
+	// remains the source of truth for the request/response shape.
+	encoding.RegisterCodec(jsonGRPCCodec{})
+}
+
+// jsonGRPCCodec implements grpc/encoding.Codec by (de)serializing messages as
+// JSON instead of protobuf wire format.
+type jsonGRPCCodec struct{}
+
+func (jsonGRPCCodec) Name() string { return "json" }
+
+func (jsonGRPCCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonGRPCCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// grpcDiagnoseRequest/grpcDiagnoseResponse mirror DiagnoseRequest/DiagnoseResponse
+// in proto/analyzer.proto.
+type grpcDiagnoseRequest struct {
+	CallID       string `json:"call_id"`
+	LogData      string `json:"log_data"`
+	AnalysisJSON string `json:"analysis_json"`
+}
+
+type grpcDiagnoseResponse struct {
+	RootCause       string         `json:"root_cause"`
+	Confidence      string         `json:"confidence"`
+	Severity        string         `json:"severity"`
+	ConfigChanges   []ConfigChange `json:"config_changes"`
+	PreventionSteps []string       `json:"prevention_steps"`
+	EvidenceRefs    []string       `json:"evidence_refs"`
+}
+
+// grpcAnalyzer is the Analyzer backend for TROUBLESHOOT_LLM_PROVIDER=grpc. It
+// lets operators point RCA at any external process implementing
+// AnalyzerService: a Python service, a private model server, a deterministic
+// rules engine, or an ensemble that queries multiple LLMs and votes.
+type grpcAnalyzer struct {
+	addr string
+}
+
+// newGRPCAnalyzer builds a grpcAnalyzer from TROUBLESHOOT_LLM_GRPC_ADDR.
+func newGRPCAnalyzer() (Analyzer, error) {
+	addr := os.Getenv("TROUBLESHOOT_LLM_GRPC_ADDR")
+	if addr == "" {
+		return nil, fmt.Errorf("TROUBLESHOOT_LLM_GRPC_ADDR is required for provider: grpc")
+	}
+	return &grpcAnalyzer{addr: addr}, nil
+}
+
+func (g *grpcAnalyzer) Analyze(ctx context.Context, analysis *Analysis, logData string) (*LLMDiagnosis, error) {
+	analysisJSON, err := json.Marshal(analysis)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal analysis: %w", err)
+	}
+
+	conn, err := grpc.NewClient(g.addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.CallContentSubtype(jsonGRPCCodec{}.Name())),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial analyzer backend %s: %w", g.addr, err)
+	}
+	defer conn.Close()
+
+	callCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	req := &grpcDiagnoseRequest{
+		CallID:       analysis.CallID,
+		LogData:      logData,
+		AnalysisJSON: string(analysisJSON),
+	}
+	resp := &grpcDiagnoseResponse{}
+	if err := conn.Invoke(callCtx, grpcDiagnoseMethod, req, resp); err != nil {
+		return nil, fmt.Errorf("analyzer backend %s: %w", g.addr, err)
+	}
+
+	structured := &StructuredDiagnosis{
+		RootCause:       resp.RootCause,
+		Confidence:      resp.Confidence,
+		Severity:        resp.Severity,
+		ConfigChanges:   resp.ConfigChanges,
+		PreventionSteps: resp.PreventionSteps,
+		EvidenceRefs:    resp.EvidenceRefs,
+	}
+
+	return &LLMDiagnosis{
+		Provider:   "grpc",
+		Model:      g.addr,
+		Analysis:   structured.FormatForDisplay(),
+		Structured: structured,
+	}, nil
+}
@@ -0,0 +1,206 @@
+package troubleshoot
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/troubleshoot/capture"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/troubleshoot/store"
+	"google.golang.org/grpc"
+)
+
+// rcaDataChunkBytes caps how much WAV data GetAudioSegment sends per
+// stream.Send, so a long segment doesn't arrive as one oversized message.
+const rcaDataChunkBytes = 32 * 1024
+
+// The request/response types below mirror proto/rca_data.proto, exchanged
+// as JSON rather than protobuf wire format — see the "json" grpc codec
+// AnalyzerService already registers in grpc_analyzer.go's init(), which
+// this server reuses.
+
+type getRCAResultRequest struct {
+	CallID string `json:"call_id"`
+}
+
+type rcaResult struct {
+	CallID     string `json:"call_id"`
+	ReportJSON string `json:"report_json"`
+}
+
+type getAudioSegmentRequest struct {
+	CallID  string `json:"call_id"`
+	StartMs int32  `json:"start_ms"`
+	EndMs   int32  `json:"end_ms"`
+	Format  string `json:"format"`
+}
+
+type audioChunk struct {
+	Data []byte `json:"data"`
+}
+
+type getPeaksRequest struct {
+	CallID  string `json:"call_id"`
+	StartMs int32  `json:"start_ms"`
+	EndMs   int32  `json:"end_ms"`
+	Buckets int32  `json:"buckets"`
+}
+
+type peaksResponse struct {
+	Buckets []int32 `json:"buckets"`
+}
+
+// rcaDataServiceServer is the interface RCADataServer must implement to
+// register as RCADataService's HandlerType — see rcaDataServiceDesc.
+type rcaDataServiceServer interface {
+	getRCAResult(ctx context.Context, req *getRCAResultRequest) (*rcaResult, error)
+	getPeaks(ctx context.Context, req *getPeaksRequest) (*peaksResponse, error)
+}
+
+// RCADataServer implements RCADataService from proto/rca_data.proto: it
+// answers GetRCAResult from the same SQLite store `agent rca --save`
+// writes to, and GetAudioSegment/GetPeaks from the pcap files `agent rca
+// --follow --capture` records (see capture.ExtractAudioSegment). A call
+// with no --capture session has no audio to serve; those two RPCs return
+// an error rather than silently empty data.
+type RCADataServer struct {
+	store      *store.Store
+	captureDir string
+}
+
+// NewRCADataServer opens storePath (the database `agent rca --save`
+// writes to) and configures captureDir as where `agent rca --follow
+// --capture` wrote its pcaps.
+func NewRCADataServer(storePath, captureDir string) (*RCADataServer, error) {
+	st, err := store.Open(storePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open RCA store: %w", err)
+	}
+	return &RCADataServer{store: st, captureDir: captureDir}, nil
+}
+
+// Close releases the underlying store handle.
+func (s *RCADataServer) Close() error {
+	return s.store.Close()
+}
+
+func (s *RCADataServer) getRCAResult(ctx context.Context, req *getRCAResultRequest) (*rcaResult, error) {
+	rec, err := s.store.Get(req.CallID)
+	if err != nil {
+		return nil, fmt.Errorf("call %q not found: %w", req.CallID, err)
+	}
+	return &rcaResult{CallID: rec.CallID, ReportJSON: rec.ReportJSON}, nil
+}
+
+func (s *RCADataServer) getPeaks(ctx context.Context, req *getPeaksRequest) (*peaksResponse, error) {
+	samples, err := s.loadSamples(req.CallID, int(req.StartMs), int(req.EndMs))
+	if err != nil {
+		return nil, err
+	}
+	buckets := int(req.Buckets)
+	if buckets <= 0 {
+		buckets = 100
+	}
+	return &peaksResponse{Buckets: capture.ComputePeaks(samples, buckets)}, nil
+}
+
+// loadSamples locates callID's pcap by convention (see capture.PcapPath)
+// and decodes the [startMs, endMs) range to PCM16.
+func (s *RCADataServer) loadSamples(callID string, startMs, endMs int) ([]int16, error) {
+	pcapPath := capture.PcapPath(s.captureDir, callID)
+	samples, err := capture.ExtractAudioSegment(pcapPath, 0, startMs, endMs)
+	if err != nil {
+		return nil, fmt.Errorf("no captured audio for call %q: %w", callID, err)
+	}
+	return samples, nil
+}
+
+var rcaDataServiceDesc = grpc.ServiceDesc{
+	ServiceName: "troubleshoot.RCADataService",
+	HandlerType: (*rcaDataServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetRCAResult",
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+				req := new(getRCAResultRequest)
+				if err := dec(req); err != nil {
+					return nil, err
+				}
+				s := srv.(*RCADataServer)
+				if interceptor == nil {
+					return s.getRCAResult(ctx, req)
+				}
+				info := &grpc.UnaryServerInfo{Server: s, FullMethod: "/troubleshoot.RCADataService/GetRCAResult"}
+				return interceptor(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+					return s.getRCAResult(ctx, req.(*getRCAResultRequest))
+				})
+			},
+		},
+		{
+			MethodName: "GetPeaks",
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+				req := new(getPeaksRequest)
+				if err := dec(req); err != nil {
+					return nil, err
+				}
+				s := srv.(*RCADataServer)
+				if interceptor == nil {
+					return s.getPeaks(ctx, req)
+				}
+				info := &grpc.UnaryServerInfo{Server: s, FullMethod: "/troubleshoot.RCADataService/GetPeaks"}
+				return interceptor(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+					return s.getPeaks(ctx, req.(*getPeaksRequest))
+				})
+			},
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "GetAudioSegment",
+			Handler:       getAudioSegmentStreamHandler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "proto/rca_data.proto",
+}
+
+// getAudioSegmentStreamHandler implements the only server-streaming RPC,
+// which grpc.ServiceDesc can't express as a plain MethodDesc.
+func getAudioSegmentStreamHandler(srv interface{}, stream grpc.ServerStream) error {
+	s := srv.(*RCADataServer)
+	req := new(getAudioSegmentRequest)
+	if err := stream.RecvMsg(req); err != nil {
+		return err
+	}
+	if req.Format != "" && req.Format != "WAV" {
+		return fmt.Errorf("format %q is not implemented yet; only WAV is supported today", req.Format)
+	}
+
+	samples, err := s.loadSamples(req.CallID, int(req.StartMs), int(req.EndMs))
+	if err != nil {
+		return err
+	}
+	wav := capture.ExportWAV(samples, 8000)
+	for offset := 0; offset < len(wav); offset += rcaDataChunkBytes {
+		end := offset + rcaDataChunkBytes
+		if end > len(wav) {
+			end = len(wav)
+		}
+		if err := stream.SendMsg(&audioChunk{Data: wav[offset:end]}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Serve registers RCADataService on a new grpc.Server and blocks serving
+// it on addr.
+func (s *RCADataServer) Serve(addr string) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	srv := grpc.NewServer()
+	srv.RegisterService(&rcaDataServiceDesc, s)
+	return srv.Serve(lis)
+}
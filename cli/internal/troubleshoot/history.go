@@ -0,0 +1,171 @@
+package troubleshoot
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/troubleshoot/store"
+)
+
+// saveReport persists rep (and the raw filtered log slice it was built
+// from) to the configured store, if any. Storage is best-effort: a failure
+// here is logged in verbose mode but never fails the RCA run itself, the
+// same tradeoff AnalyzeWithLLM's errors get in Run.
+func (r *Runner) saveReport(rep *RCAReport, logData string) {
+	if r.storePath == "" {
+		return
+	}
+	st, err := store.Open(r.storePath)
+	if err != nil {
+		if r.verbose {
+			fmt.Fprintf(os.Stderr, "[DEBUG] failed to open RCA store %q: %v\n", r.storePath, err)
+		}
+		return
+	}
+	defer st.Close()
+
+	reportJSON, err := json.Marshal(rep)
+	if err != nil {
+		return
+	}
+
+	providerName := ""
+	if rep.Header != nil {
+		providerName = rep.Header.ProviderName
+	}
+	var driftPct float64
+	var underflowCount, gateClosures int
+	if rep.Metrics != nil {
+		driftPct = abs(rep.Metrics.WorstDriftPct)
+		underflowCount = rep.Metrics.UnderflowCount
+		gateClosures = rep.Metrics.GateClosures
+	}
+
+	rec := store.Record{
+		CallID:         rep.CallID,
+		ProviderName:   providerName,
+		AudioTransport: rep.AudioTransport,
+		Symptom:        rep.Symptom,
+		DriftPct:       driftPct,
+		UnderflowCount: underflowCount,
+		GateClosures:   gateClosures,
+		ReportJSON:     string(reportJSON),
+		LogData:        logData,
+		ErrorsText:     strings.Join(append(append([]string{}, rep.Errors...), rep.Warnings...), "\n"),
+	}
+	if err := st.Save(rec); err != nil && r.verbose {
+		fmt.Fprintf(os.Stderr, "[DEBUG] failed to save RCA report for call %s: %v\n", rep.CallID, err)
+	}
+}
+
+// HistoryOptions configures RunHistory (`agent rca history`): the filter DSL
+// expression and how many matching calls to print.
+type HistoryOptions struct {
+	Filter string // e.g. `provider=deepgram transport=externalmedia drift_pct>5 since=24h grep="underflow"`
+	Limit  int
+}
+
+func (o HistoryOptions) withDefaults() HistoryOptions {
+	if o.Limit <= 0 {
+		o.Limit = 50
+	}
+	return o
+}
+
+// RunHistory searches the RCA store for calls matching opts.Filter and
+// prints their summaries (newest first), so "find all calls with X problem
+// in the last N days" doesn't require the original ai_engine logs to still
+// be around.
+func (r *Runner) RunHistory(opts HistoryOptions) error {
+	if r.storePath == "" {
+		return fmt.Errorf("no RCA store configured; set --store-path or RCA_STORE_PATH")
+	}
+	opts = opts.withDefaults()
+
+	st, err := store.Open(r.storePath)
+	if err != nil {
+		return fmt.Errorf("failed to open RCA store %q: %w", r.storePath, err)
+	}
+	defer st.Close()
+
+	filter, err := store.ParseQuery(opts.Filter)
+	if err != nil {
+		return fmt.Errorf("invalid filter: %w", err)
+	}
+
+	summaries, err := st.Query(filter)
+	if err != nil {
+		return fmt.Errorf("query failed: %w", err)
+	}
+	if len(summaries) > opts.Limit {
+		summaries = summaries[:opts.Limit]
+	}
+
+	if r.jsonOutput {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(summaries)
+	}
+
+	if len(summaries) == 0 {
+		fmt.Println("No stored calls match that filter.")
+		return nil
+	}
+	fmt.Printf("%-24s %-12s %-14s %-16s %8s %10s %8s\n", "CALL ID", "PROVIDER", "TRANSPORT", "SYMPTOM", "DRIFT%", "UNDERFLOW", "GATES")
+	for _, s := range summaries {
+		fmt.Printf("%-24s %-12s %-14s %-16s %8.2f %10d %8d\n", s.CallID, orDash(s.ProviderName), orDash(s.AudioTransport), orDash(s.Symptom), s.DriftPct, s.UnderflowCount, s.GateClosures)
+	}
+	return nil
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// RunQuery re-renders a stored RCAReport by call_id, without needing the
+// original ai_engine logs (which may have rotated away).
+func (r *Runner) RunQuery(callID string) error {
+	if r.storePath == "" {
+		return fmt.Errorf("no RCA store configured; set --store-path or RCA_STORE_PATH")
+	}
+	st, err := store.Open(r.storePath)
+	if err != nil {
+		return fmt.Errorf("failed to open RCA store %q: %w", r.storePath, err)
+	}
+	defer st.Close()
+
+	rec, err := st.Get(callID)
+	if err != nil {
+		return fmt.Errorf("no stored report for call %q: %w", callID, err)
+	}
+
+	if r.jsonOutput {
+		fmt.Println(rec.ReportJSON)
+		return nil
+	}
+
+	var rep RCAReport
+	if err := json.Unmarshal([]byte(rec.ReportJSON), &rep); err != nil {
+		return fmt.Errorf("failed to decode stored report: %w", err)
+	}
+	fmt.Printf("\n━━━ Stored RCA report: %s (saved %s) ━━━\n", rec.CallID, rec.CreatedAt.Format("2006-01-02 15:04:05 MST"))
+	r.displayHeader(rep.Header, rep.ProviderRuntime)
+	r.displayFindings(&Analysis{
+		CallID: rep.CallID, Errors: rep.Errors, Warnings: rep.Warnings, AudioIssues: rep.AudioIssues,
+		AudioTransport: rep.AudioTransport, HasAudioSocket: rep.Pipeline.HasAudioSocket,
+		HasExternalMedia: rep.Pipeline.HasExternalMedia, HasTranscription: rep.Pipeline.HasTranscription,
+		HasPlayback: rep.Pipeline.HasPlayback, Symptom: rep.Symptom, SymptomAnalysis: rep.SymptomAnalysis,
+	})
+	if rep.Metrics != nil {
+		r.displayMetrics(rep.Metrics)
+	}
+	if rep.LLMDiagnosis != nil {
+		r.displayLLMDiagnosis(rep.LLMDiagnosis)
+	}
+	return nil
+}
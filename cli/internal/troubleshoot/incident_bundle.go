@@ -0,0 +1,189 @@
+package troubleshoot
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// redactConfigKeyPattern matches a YAML "key: value" line whose key looks
+// like a credential, so ExportIncidentBundle can strip it from
+// config/ai-agent.yaml before bundling the file for a support ticket.
+var redactConfigKeyPattern = regexp.MustCompile(`(?i)^(\s*[\w-]*(api_key|secret|token|password)[\w-]*\s*:\s*).+$`)
+
+// redactConfigYAML replaces the value of any credential-looking key with
+// "<redacted>", line by line, leaving everything else untouched.
+func redactConfigYAML(data []byte) []byte {
+	lines := strings.Split(string(data), "\n")
+	for i, line := range lines {
+		if redactConfigKeyPattern.MatchString(line) {
+			lines[i] = redactConfigKeyPattern.ReplaceAllString(line, "${1}<redacted>")
+		}
+	}
+	return []byte(strings.Join(lines, "\n"))
+}
+
+// findConfigYAML looks for config/ai-agent.yaml in the cwd, then one level
+// up — the same fallback wizard.Config uses (see wizard/config.go).
+func findConfigYAML() (path string, data []byte, ok bool) {
+	for _, p := range []string{"config/ai-agent.yaml", "../config/ai-agent.yaml"} {
+		if d, err := os.ReadFile(p); err == nil {
+			return p, d, true
+		}
+	}
+	return "", nil, false
+}
+
+// renderIncidentMarkdown builds a Markdown incident report carrying the
+// same information the terminal's display* functions print, structured for
+// reading outside a terminal (support tickets, tracked issues).
+func renderIncidentMarkdown(analysis *Analysis, llm *LLMDiagnosis) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# RCA Incident Report: %s\n\n", analysis.CallID)
+
+	fmt.Fprintf(&b, "## Header\n\n")
+	fmt.Fprintf(&b, "- Audio transport: %s\n", analysis.AudioTransport)
+	if analysis.ProviderRuntime != nil {
+		fmt.Fprintf(&b, "- Provider: %s\n", analysis.ProviderRuntime.ProviderName)
+	}
+	fmt.Fprintf(&b, "- AudioSocket: %v, ExternalMedia: %v, Transcription: %v, Playback: %v\n\n",
+		analysis.HasAudioSocket, analysis.HasExternalMedia, analysis.HasTranscription, analysis.HasPlayback)
+
+	if len(analysis.Errors) > 0 {
+		fmt.Fprintf(&b, "## Errors (%d)\n\n", len(analysis.Errors))
+		for _, e := range analysis.Errors {
+			fmt.Fprintf(&b, "- %s\n", e)
+		}
+		b.WriteString("\n")
+	}
+
+	if len(analysis.Warnings) > 0 {
+		fmt.Fprintf(&b, "## Warnings (%d)\n\n", len(analysis.Warnings))
+		for _, w := range analysis.Warnings {
+			fmt.Fprintf(&b, "- %s\n", w)
+		}
+		b.WriteString("\n")
+	}
+
+	if score, issues, ok := computeCallQuality(analysis); ok {
+		b.WriteString("## Call Quality\n\n")
+		fmt.Fprintf(&b, "- Score: %.0f/100\n", score)
+		if analysis.Metrics != nil && analysis.Metrics.MOS > 0 {
+			fmt.Fprintf(&b, "- Estimated MOS: %.2f/4.5\n", analysis.Metrics.MOS)
+		}
+		if len(issues) > 0 {
+			b.WriteString("\nIssues:\n\n")
+			for _, iss := range issues {
+				fmt.Fprintf(&b, "- %s\n", iss)
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	if analysis.GoldenBaselineChecks != nil {
+		fmt.Fprintf(&b, "## Baseline Diff: %s\n\n", analysis.GoldenBaselineName)
+		b.WriteString("| Check | Expected | Actual | Result |\n|---|---|---|---|\n")
+		for _, c := range analysis.GoldenBaselineChecks {
+			result := "PASS"
+			if !c.Pass {
+				result = "FAIL"
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", c.Name, c.Expected, c.Actual, result)
+		}
+		b.WriteString("\n")
+	}
+
+	if sa := analysis.SymptomAnalysis; sa != nil {
+		fmt.Fprintf(&b, "## Symptom: %s\n\n%s\n\n", sa.Symptom, sa.Description)
+		if len(sa.Findings) > 0 {
+			b.WriteString("Findings:\n\n")
+			for _, f := range sa.Findings {
+				fmt.Fprintf(&b, "- %s\n", f)
+			}
+			b.WriteString("\n")
+		}
+		if len(sa.RootCauses) > 0 {
+			b.WriteString("Root causes:\n\n")
+			for _, rc := range sa.RootCauses {
+				fmt.Fprintf(&b, "- %s\n", rc)
+			}
+			b.WriteString("\n")
+		}
+		if len(sa.Actions) > 0 {
+			b.WriteString("Recommended actions:\n\n")
+			for _, a := range sa.Actions {
+				fmt.Fprintf(&b, "- %s\n", a)
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	if llm != nil {
+		fmt.Fprintf(&b, "## AI Diagnosis (%s/%s)\n\n%s\n\n", llm.Provider, llm.Model, llm.Analysis)
+	}
+
+	return b.String()
+}
+
+// ExportIncidentBundle writes a zip at path containing everything a support
+// engineer needs to triage this call without re-running `agent rca`: the
+// raw log slice, the full Analysis/CallMetrics as JSON, a Markdown report,
+// and a redacted copy of config/ai-agent.yaml (API keys/secrets stripped,
+// see redactConfigYAML). It returns the bundle's SHA-256 so the caller can
+// print it for integrity verification.
+func (r *Runner) ExportIncidentBundle(analysis *Analysis, llm *LLMDiagnosis, logData, path string) (string, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to create incident bundle: %w", err)
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	writeEntry := func(name string, data []byte) error {
+		w, err := zw.Create(name)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	}
+
+	if err := writeEntry("call.log", []byte(logData)); err != nil {
+		return "", err
+	}
+
+	rep := buildRCAReport(analysis, llm)
+	reportJSON, err := json.MarshalIndent(rep, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := writeEntry("report.json", reportJSON); err != nil {
+		return "", err
+	}
+
+	if err := writeEntry("report.md", []byte(renderIncidentMarkdown(analysis, llm))); err != nil {
+		return "", err
+	}
+
+	if _, data, ok := findConfigYAML(); ok {
+		if err := writeEntry("ai-agent.redacted.yaml", redactConfigYAML(data)); err != nil {
+			return "", err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return "", err
+	}
+
+	bundleData, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(bundleData)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -1,7 +1,9 @@
 package troubleshoot
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -16,6 +18,7 @@ type LLMAnalyzer struct {
 	provider string
 	apiKey   string
 	model    string
+	baseURL  string // only set for OpenAI-compatible self-hosted providers
 }
 
 // NewLLMAnalyzer creates an LLM analyzer
@@ -33,7 +36,7 @@ func NewLLMAnalyzer() (*LLMAnalyzer, error) {
 		}
 	}
 
-	var apiKey, model string
+	var apiKey, model, baseURL string
 	switch provider {
 	case "openai":
 		apiKey = os.Getenv("OPENAI_API_KEY")
@@ -41,33 +44,134 @@ func NewLLMAnalyzer() (*LLMAnalyzer, error) {
 	case "anthropic":
 		apiKey = os.Getenv("ANTHROPIC_API_KEY")
 		model = "claude-3-haiku-20240307" // Fast and cost-effective
+	case "localai":
+		baseURL = os.Getenv("TROUBLESHOOT_LLM_BASE_URL")
+		if baseURL == "" {
+			baseURL = "http://127.0.0.1:8080/v1"
+		}
+		model = os.Getenv("TROUBLESHOOT_LLM_MODEL")
+		apiKey = os.Getenv("TROUBLESHOOT_LLM_API_KEY") // most LocalAI deployments don't require one
+	case "ollama":
+		baseURL = os.Getenv("TROUBLESHOOT_LLM_BASE_URL")
+		if baseURL == "" {
+			baseURL = "http://127.0.0.1:11434/v1"
+		}
+		model = os.Getenv("TROUBLESHOOT_LLM_MODEL")
+		apiKey = os.Getenv("TROUBLESHOOT_LLM_API_KEY") // Ollama's OpenAI-compatible endpoint ignores this
+	case "azure_openai":
+		apiKey = os.Getenv("AZURE_OPENAI_API_KEY")
+		model = os.Getenv("AZURE_OPENAI_DEPLOYMENT")
 	default:
 		return nil, fmt.Errorf("unsupported provider: %s", provider)
 	}
 
-	if apiKey == "" {
-		return nil, fmt.Errorf("no API key found for provider: %s", provider)
+	if provider == "openai" || provider == "anthropic" || provider == "azure_openai" {
+		if apiKey == "" {
+			return nil, fmt.Errorf("no API key found for provider: %s", provider)
+		}
+	}
+	if (provider == "localai" || provider == "ollama") && model == "" {
+		return nil, fmt.Errorf("TROUBLESHOOT_LLM_MODEL is required for provider: %s", provider)
+	}
+	if provider == "azure_openai" {
+		if os.Getenv("AZURE_OPENAI_ENDPOINT") == "" {
+			return nil, fmt.Errorf("AZURE_OPENAI_ENDPOINT is required for provider: azure_openai")
+		}
+		if model == "" {
+			return nil, fmt.Errorf("AZURE_OPENAI_DEPLOYMENT is required for provider: azure_openai")
+		}
 	}
 
 	return &LLMAnalyzer{
 		provider: provider,
 		apiKey:   apiKey,
 		model:    model,
+		baseURL:  baseURL,
 	}, nil
 }
 
-// AnalyzeWithLLM performs AI-powered analysis
+// azureChatCompletionsURL builds the Azure OpenAI request URL from env vars:
+// https://{resource}.openai.azure.com/openai/deployments/{deployment}/chat/completions?api-version=...
+func azureChatCompletionsURL(deployment string) string {
+	endpoint := strings.TrimRight(os.Getenv("AZURE_OPENAI_ENDPOINT"), "/")
+	apiVersion := os.Getenv("AZURE_OPENAI_API_VERSION")
+	if apiVersion == "" {
+		apiVersion = "2024-06-01"
+	}
+	return fmt.Sprintf("%s/openai/deployments/%s/chat/completions?api-version=%s", endpoint, deployment, apiVersion)
+}
+
+// AnalyzeWithLLM performs AI-powered analysis. It asks the provider for a
+// structured diagnosis (function-calling for OpenAI-shaped providers, a
+// tools block for Anthropic) so downstream tooling can act on ConfigChange
+// entries directly, and also renders a human-readable Analysis string from
+// the same structured result for display/backward compatibility.
 func (llm *LLMAnalyzer) AnalyzeWithLLM(analysis *Analysis, logData string) (*LLMDiagnosis, error) {
-	prompt := llm.buildPrompt(analysis, logData)
+	return llm.AnalyzeWithLLMCtx(context.Background(), analysis, logData)
+}
+
+// AnalyzeWithLLMCtx is AnalyzeWithLLM with cancellation: ctx is honored on
+// the underlying HTTP request, so a caller (e.g. a TUI) can abandon a slow
+// diagnosis instead of blocking for the full 30s timeout.
+func (llm *LLMAnalyzer) AnalyzeWithLLMCtx(ctx context.Context, analysis *Analysis, logData string) (*LLMDiagnosis, error) {
+	prompt := llm.buildPrompt(analysis, logData) + structuredDiagnosisInstructions
+
+	var structured *StructuredDiagnosis
+	var usage llmUsage
+	var err error
+
+	switch llm.provider {
+	case "openai":
+		structured, usage, err = llm.callOpenAICompatibleStructured(ctx, "https://api.openai.com/v1/chat/completions", llm.apiKey, prompt)
+	case "anthropic":
+		structured, usage, err = llm.callAnthropicStructured(ctx, prompt)
+	case "localai", "ollama":
+		structured, usage, err = llm.callOpenAICompatibleStructured(ctx, strings.TrimRight(llm.baseURL, "/")+"/chat/completions", llm.apiKey, prompt)
+	case "azure_openai":
+		structured, usage, err = llm.callAzureOpenAIStructured(ctx, prompt)
+	default:
+		return nil, fmt.Errorf("unsupported provider: %s", llm.provider)
+	}
 
-	var response string
+	if err != nil {
+		return nil, err
+	}
+
+	return &LLMDiagnosis{
+		Provider:         llm.provider,
+		Model:            llm.model,
+		Analysis:         structured.FormatForDisplay(),
+		Structured:       structured,
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		CostUSD:          estimateCostUSD(llm.model, usage.PromptTokens, usage.CompletionTokens),
+	}, nil
+}
+
+// AnalyzeWithLLMStream is AnalyzeWithLLMCtx but forwards token-level text as
+// it arrives on out, for a TUI/CLI progress display, then returns the same
+// structured diagnosis once the stream completes. out is never closed by
+// this function; the caller owns it.
+func (llm *LLMAnalyzer) AnalyzeWithLLMStream(ctx context.Context, analysis *Analysis, logData string, out chan<- string) (*LLMDiagnosis, error) {
+	prompt := llm.buildPrompt(analysis, logData) + structuredDiagnosisInstructions
+
+	var structured *StructuredDiagnosis
+	var usage llmUsage
 	var err error
 
 	switch llm.provider {
 	case "openai":
-		response, err = llm.callOpenAI(prompt)
+		structured, usage, err = llm.streamOpenAICompatible(ctx, "https://api.openai.com/v1/chat/completions", map[string]string{"Authorization": "Bearer " + llm.apiKey}, prompt, out)
 	case "anthropic":
-		response, err = llm.callAnthropic(prompt)
+		structured, usage, err = llm.streamAnthropic(ctx, prompt, out)
+	case "localai", "ollama":
+		headers := map[string]string{}
+		if llm.apiKey != "" {
+			headers["Authorization"] = "Bearer " + llm.apiKey
+		}
+		structured, usage, err = llm.streamOpenAICompatible(ctx, strings.TrimRight(llm.baseURL, "/")+"/chat/completions", headers, prompt, out)
+	case "azure_openai":
+		structured, usage, err = llm.streamOpenAICompatible(ctx, azureChatCompletionsURL(llm.model), map[string]string{"api-key": llm.apiKey}, prompt, out)
 	default:
 		return nil, fmt.Errorf("unsupported provider: %s", llm.provider)
 	}
@@ -77,9 +181,13 @@ func (llm *LLMAnalyzer) AnalyzeWithLLM(analysis *Analysis, logData string) (*LLM
 	}
 
 	return &LLMDiagnosis{
-		Provider: llm.provider,
-		Model:    llm.model,
-		Analysis: response,
+		Provider:         llm.provider,
+		Model:            llm.model,
+		Analysis:         structured.FormatForDisplay(),
+		Structured:       structured,
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		CostUSD:          estimateCostUSD(llm.model, usage.PromptTokens, usage.CompletionTokens),
 	}, nil
 }
 
@@ -255,10 +363,49 @@ func (llm *LLMAnalyzer) buildPrompt(analysis *Analysis, logData string) string {
 	return prompt.String()
 }
 
-// callOpenAI makes OpenAI API request
-func (llm *LLMAnalyzer) callOpenAI(prompt string) (string, error) {
-	url := "https://api.openai.com/v1/chat/completions"
+// emitDiagnosisTool is the OpenAI-shaped tool definition forcing the model
+// to return a StructuredDiagnosis instead of free text.
+func emitDiagnosisTool() map[string]interface{} {
+	var schema interface{}
+	_ = json.Unmarshal([]byte(emitDiagnosisSchema), &schema)
+	return map[string]interface{}{
+		"type": "function",
+		"function": map[string]interface{}{
+			"name":        "emit_diagnosis",
+			"description": "Emit the structured RCA diagnosis.",
+			"parameters":  schema,
+		},
+	}
+}
+
+// callOpenAICompatibleStructured calls any provider that implements the
+// OpenAI /v1/chat/completions + tools surface: OpenAI itself, plus
+// self-hosted gateways (LocalAI, Ollama, vLLM, llama.cpp server, TGI) that
+// mirror it. apiKey may be empty since most self-hosted gateways don't
+// require one.
+func (llm *LLMAnalyzer) callOpenAICompatibleStructured(ctx context.Context, url, apiKey, prompt string) (*StructuredDiagnosis, llmUsage, error) {
+	headers := map[string]string{}
+	if apiKey != "" {
+		headers["Authorization"] = "Bearer " + apiKey
+	}
+	return llm.doChatCompletionRequestStructured(ctx, url, headers, prompt)
+}
+
+// callAzureOpenAIStructured calls an Azure OpenAI deployment. Azure uses the
+// same chat-completions + tools request/response shape as OpenAI but
+// authenticates with an "api-key" header instead of "Authorization: Bearer".
+func (llm *LLMAnalyzer) callAzureOpenAIStructured(ctx context.Context, prompt string) (*StructuredDiagnosis, llmUsage, error) {
+	url := azureChatCompletionsURL(llm.model)
+	headers := map[string]string{"api-key": llm.apiKey}
+	return llm.doChatCompletionRequestStructured(ctx, url, headers, prompt)
+}
 
+// doChatCompletionRequestStructured issues the shared OpenAI-shaped
+// chat-completions request with tool_choice forced to emit_diagnosis, and
+// parses the tool call arguments into a StructuredDiagnosis. url and headers
+// carry whatever differs between OpenAI, Azure, and OpenAI-compatible
+// self-hosted gateways.
+func (llm *LLMAnalyzer) doChatCompletionRequestStructured(ctx context.Context, url string, headers map[string]string, prompt string) (*StructuredDiagnosis, llmUsage, error) {
 	requestBody := map[string]interface{}{
 		"model": llm.model,
 		"messages": []map[string]string{
@@ -269,64 +416,90 @@ func (llm *LLMAnalyzer) callOpenAI(prompt string) (string, error) {
 		},
 		"max_tokens":  800,
 		"temperature": 0.3,
+		"tools":       []interface{}{emitDiagnosisTool()},
+		"tool_choice": map[string]interface{}{
+			"type":     "function",
+			"function": map[string]string{"name": "emit_diagnosis"},
+		},
 	}
 
 	jsonData, err := json.Marshal(requestBody)
 	if err != nil {
-		return "", err
+		return nil, llmUsage{}, err
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
 	if err != nil {
-		return "", err
+		return nil, llmUsage{}, err
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+llm.apiKey)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
 
 	client := &http.Client{Timeout: 30 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("OpenAI request failed: %w", err)
+		return nil, llmUsage{}, fmt.Errorf("LLM request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", err
+		return nil, llmUsage{}, err
 	}
 
 	if resp.StatusCode != 200 {
-		return "", fmt.Errorf("OpenAI API error %d: %s", resp.StatusCode, string(body))
+		return nil, llmUsage{}, fmt.Errorf("LLM API error %d: %s", resp.StatusCode, string(body))
 	}
 
 	var result map[string]interface{}
 	if err := json.Unmarshal(body, &result); err != nil {
-		return "", err
+		return nil, llmUsage{}, err
 	}
 
+	usage := parseOpenAIUsage(result)
+
 	choices, ok := result["choices"].([]interface{})
 	if !ok || len(choices) == 0 {
-		return "", fmt.Errorf("no response from OpenAI")
+		return nil, usage, fmt.Errorf("no response from LLM")
 	}
 
 	message, ok := choices[0].(map[string]interface{})["message"].(map[string]interface{})
 	if !ok {
-		return "", fmt.Errorf("invalid response format")
+		return nil, usage, fmt.Errorf("invalid response format")
 	}
 
-	content, ok := message["content"].(string)
-	if !ok {
-		return "", fmt.Errorf("no content in response")
+	if toolCalls, ok := message["tool_calls"].([]interface{}); ok && len(toolCalls) > 0 {
+		fn, ok := toolCalls[0].(map[string]interface{})["function"].(map[string]interface{})
+		if ok {
+			if args, ok := fn["arguments"].(string); ok {
+				d, err := parseStructuredDiagnosis(args)
+				return d, usage, err
+			}
+		}
+	}
+
+	// Some OpenAI-compatible gateways don't implement tool_choice and just
+	// echo JSON back as message content instead.
+	if content, ok := message["content"].(string); ok && content != "" {
+		d, err := parseStructuredDiagnosis(content)
+		return d, usage, err
 	}
 
-	return content, nil
+	return nil, usage, fmt.Errorf("no tool call or content in response")
 }
 
-// callAnthropic makes Anthropic API request
-func (llm *LLMAnalyzer) callAnthropic(prompt string) (string, error) {
+// callAnthropicStructured makes an Anthropic request using its tools block,
+// with tool_choice forced to emit_diagnosis so Claude returns a
+// StructuredDiagnosis instead of free text.
+func (llm *LLMAnalyzer) callAnthropicStructured(ctx context.Context, prompt string) (*StructuredDiagnosis, llmUsage, error) {
 	url := "https://api.anthropic.com/v1/messages"
 
+	var schema interface{}
+	_ = json.Unmarshal([]byte(emitDiagnosisSchema), &schema)
+
 	requestBody := map[string]interface{}{
 		"model": llm.model,
 		"messages": []map[string]string{
@@ -336,16 +509,24 @@ func (llm *LLMAnalyzer) callAnthropic(prompt string) (string, error) {
 			},
 		},
 		"max_tokens": 800,
+		"tools": []interface{}{
+			map[string]interface{}{
+				"name":         "emit_diagnosis",
+				"description":  "Emit the structured RCA diagnosis.",
+				"input_schema": schema,
+			},
+		},
+		"tool_choice": map[string]string{"type": "tool", "name": "emit_diagnosis"},
 	}
 
 	jsonData, err := json.Marshal(requestBody)
 	if err != nil {
-		return "", err
+		return nil, llmUsage{}, err
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
 	if err != nil {
-		return "", err
+		return nil, llmUsage{}, err
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -355,40 +536,348 @@ func (llm *LLMAnalyzer) callAnthropic(prompt string) (string, error) {
 	client := &http.Client{Timeout: 30 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("Anthropic request failed: %w", err)
+		return nil, llmUsage{}, fmt.Errorf("Anthropic request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", err
+		return nil, llmUsage{}, err
 	}
 
 	if resp.StatusCode != 200 {
-		return "", fmt.Errorf("Anthropic API error %d: %s", resp.StatusCode, string(body))
+		return nil, llmUsage{}, fmt.Errorf("Anthropic API error %d: %s", resp.StatusCode, string(body))
 	}
 
 	var result map[string]interface{}
 	if err := json.Unmarshal(body, &result); err != nil {
-		return "", err
+		return nil, llmUsage{}, err
 	}
 
+	usage := parseAnthropicUsage(result)
+
 	content, ok := result["content"].([]interface{})
 	if !ok || len(content) == 0 {
-		return "", fmt.Errorf("no content in response")
+		return nil, usage, fmt.Errorf("no content in response")
+	}
+
+	for _, block := range content {
+		b, ok := block.(map[string]interface{})
+		if !ok || b["type"] != "tool_use" {
+			continue
+		}
+		input, ok := b["input"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		raw, err := json.Marshal(input)
+		if err != nil {
+			return nil, usage, err
+		}
+		d, err := parseStructuredDiagnosis(string(raw))
+		return d, usage, err
+	}
+
+	return nil, usage, fmt.Errorf("no tool_use block in Anthropic response")
+}
+
+// llmUsage is the token accounting returned alongside a diagnosis, parsed
+// from the provider's "usage" object (or accumulated from streaming usage
+// events) and used to estimate CostUSD.
+type llmUsage struct {
+	PromptTokens     int
+	CompletionTokens int
+}
+
+// parseOpenAIUsage reads the "usage" object shared by OpenAI, Azure OpenAI,
+// and OpenAI-compatible self-hosted gateways.
+func parseOpenAIUsage(result map[string]interface{}) llmUsage {
+	usage, ok := result["usage"].(map[string]interface{})
+	if !ok {
+		return llmUsage{}
+	}
+	return llmUsage{
+		PromptTokens:     int(asFloat(usage["prompt_tokens"])),
+		CompletionTokens: int(asFloat(usage["completion_tokens"])),
+	}
+}
+
+// parseAnthropicUsage reads Anthropic's "usage" object, which uses
+// input_tokens/output_tokens instead of OpenAI's prompt_tokens/completion_tokens.
+func parseAnthropicUsage(result map[string]interface{}) llmUsage {
+	usage, ok := result["usage"].(map[string]interface{})
+	if !ok {
+		return llmUsage{}
 	}
+	return llmUsage{
+		PromptTokens:     int(asFloat(usage["input_tokens"])),
+		CompletionTokens: int(asFloat(usage["output_tokens"])),
+	}
+}
 
-	text, ok := content[0].(map[string]interface{})["text"].(string)
+func asFloat(v interface{}) float64 {
+	f, _ := v.(float64)
+	return f
+}
+
+// llmPricePerMToken is a small per-model price table (USD per 1M tokens) used
+// to estimate CostUSD. Prices are approximate and only meant to give
+// operators a rough sense of spend; they are not billing-accurate.
+var llmPricePerMToken = map[string]struct{ Prompt, Completion float64 }{
+	"gpt-4o-mini":                {Prompt: 0.15, Completion: 0.60},
+	"gpt-4o":                     {Prompt: 2.50, Completion: 10.00},
+	"claude-3-haiku-20240307":    {Prompt: 0.25, Completion: 1.25},
+	"claude-3-5-sonnet-20241022": {Prompt: 3.00, Completion: 15.00},
+}
+
+// llmDefaultPricePerMToken is used for models missing from llmPricePerMToken
+// (self-hosted models, unreleased models, etc.) so CostUSD still gives a
+// ballpark figure instead of reading as exactly zero.
+var llmDefaultPricePerMToken = struct{ Prompt, Completion float64 }{Prompt: 0.50, Completion: 1.50}
+
+// estimateCostUSD gives a rough per-call cost estimate from token counts.
+// Self-hosted providers (LocalAI, Ollama) have no real per-token cost; callers
+// should treat a near-zero result for those providers as informational only.
+func estimateCostUSD(model string, promptTokens, completionTokens int) float64 {
+	price, ok := llmPricePerMToken[model]
 	if !ok {
-		return "", fmt.Errorf("invalid response format")
+		price = llmDefaultPricePerMToken
 	}
+	return (float64(promptTokens)/1_000_000)*price.Prompt + (float64(completionTokens)/1_000_000)*price.Completion
+}
 
-	return text, nil
+// streamOpenAICompatible issues the chat-completions request with
+// "stream": true and parses the SSE "data: {...}" chunks OpenAI-shaped
+// providers emit, forwarding incremental tool-call argument fragments to out
+// as they arrive and accumulating them into a StructuredDiagnosis once the
+// stream ends with "data: [DONE]".
+func (llm *LLMAnalyzer) streamOpenAICompatible(ctx context.Context, url string, headers map[string]string, prompt string, out chan<- string) (*StructuredDiagnosis, llmUsage, error) {
+	requestBody := map[string]interface{}{
+		"model": llm.model,
+		"messages": []map[string]string{
+			{
+				"role":    "user",
+				"content": prompt,
+			},
+		},
+		"max_tokens":  800,
+		"temperature": 0.3,
+		"stream":      true,
+		"tools":       []interface{}{emitDiagnosisTool()},
+		"tool_choice": map[string]interface{}{
+			"type":     "function",
+			"function": map[string]string{"name": "emit_diagnosis"},
+		},
+	}
+
+	jsonData, err := json.Marshal(requestBody)
+	if err != nil {
+		return nil, llmUsage{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, llmUsage{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	client := &http.Client{Timeout: 120 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, llmUsage{}, fmt.Errorf("LLM request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, llmUsage{}, fmt.Errorf("LLM API error %d: %s", resp.StatusCode, string(body))
+	}
+
+	var argsBuilder strings.Builder
+	var contentBuilder strings.Builder
+	usage := llmUsage{}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if data == "[DONE]" {
+			break
+		}
+
+		var chunk map[string]interface{}
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			continue
+		}
+		if u, ok := chunk["usage"].(map[string]interface{}); ok {
+			usage = llmUsage{PromptTokens: int(asFloat(u["prompt_tokens"])), CompletionTokens: int(asFloat(u["completion_tokens"]))}
+		}
+		choices, ok := chunk["choices"].([]interface{})
+		if !ok || len(choices) == 0 {
+			continue
+		}
+		delta, ok := choices[0].(map[string]interface{})["delta"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if toolCalls, ok := delta["tool_calls"].([]interface{}); ok && len(toolCalls) > 0 {
+			fn, ok := toolCalls[0].(map[string]interface{})["function"].(map[string]interface{})
+			if ok {
+				if frag, ok := fn["arguments"].(string); ok && frag != "" {
+					argsBuilder.WriteString(frag)
+					out <- frag
+				}
+			}
+		}
+		if frag, ok := delta["content"].(string); ok && frag != "" {
+			contentBuilder.WriteString(frag)
+			out <- frag
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, usage, fmt.Errorf("LLM stream read failed: %w", err)
+	}
+
+	if argsBuilder.Len() > 0 {
+		d, err := parseStructuredDiagnosis(argsBuilder.String())
+		return d, usage, err
+	}
+	if contentBuilder.Len() > 0 {
+		d, err := parseStructuredDiagnosis(contentBuilder.String())
+		return d, usage, err
+	}
+	return nil, usage, fmt.Errorf("no tool call or content in streamed response")
+}
+
+// streamAnthropic issues an Anthropic messages request with "stream": true
+// and consumes the SSE event stream (event: content_block_delta /
+// message_delta), forwarding incremental tool input JSON fragments to out and
+// accumulating them into a StructuredDiagnosis.
+func (llm *LLMAnalyzer) streamAnthropic(ctx context.Context, prompt string, out chan<- string) (*StructuredDiagnosis, llmUsage, error) {
+	url := "https://api.anthropic.com/v1/messages"
+
+	var schema interface{}
+	_ = json.Unmarshal([]byte(emitDiagnosisSchema), &schema)
+
+	requestBody := map[string]interface{}{
+		"model": llm.model,
+		"messages": []map[string]string{
+			{
+				"role":    "user",
+				"content": prompt,
+			},
+		},
+		"max_tokens": 800,
+		"stream":     true,
+		"tools": []interface{}{
+			map[string]interface{}{
+				"name":         "emit_diagnosis",
+				"description":  "Emit the structured RCA diagnosis.",
+				"input_schema": schema,
+			},
+		},
+		"tool_choice": map[string]string{"type": "tool", "name": "emit_diagnosis"},
+	}
+
+	jsonData, err := json.Marshal(requestBody)
+	if err != nil {
+		return nil, llmUsage{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, llmUsage{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("x-api-key", llm.apiKey)
+	req.Header.Set("anthropic-version", "2023-06-01")
+
+	client := &http.Client{Timeout: 120 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, llmUsage{}, fmt.Errorf("Anthropic request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, llmUsage{}, fmt.Errorf("Anthropic API error %d: %s", resp.StatusCode, string(body))
+	}
+
+	var argsBuilder strings.Builder
+	usage := llmUsage{}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	var event string
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "event:") {
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+			continue
+		}
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+
+		var payload map[string]interface{}
+		if err := json.Unmarshal([]byte(data), &payload); err != nil {
+			continue
+		}
+
+		switch event {
+		case "message_start":
+			if msg, ok := payload["message"].(map[string]interface{}); ok {
+				if u, ok := msg["usage"].(map[string]interface{}); ok {
+					usage.PromptTokens = int(asFloat(u["input_tokens"]))
+				}
+			}
+		case "content_block_delta":
+			delta, ok := payload["delta"].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if frag, ok := delta["partial_json"].(string); ok && frag != "" {
+				argsBuilder.WriteString(frag)
+				out <- frag
+			}
+		case "message_delta":
+			if u, ok := payload["usage"].(map[string]interface{}); ok {
+				usage.CompletionTokens = int(asFloat(u["output_tokens"]))
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, usage, fmt.Errorf("Anthropic stream read failed: %w", err)
+	}
+
+	if argsBuilder.Len() == 0 {
+		return nil, usage, fmt.Errorf("no tool input streamed from Anthropic")
+	}
+	d, err := parseStructuredDiagnosis(argsBuilder.String())
+	return d, usage, err
 }
 
 // LLMDiagnosis holds LLM analysis results
 type LLMDiagnosis struct {
-	Provider string `json:"provider"`
-	Model    string `json:"model"`
-	Analysis string `json:"analysis"`
+	Provider         string               `json:"provider"`
+	Model            string               `json:"model"`
+	Analysis         string               `json:"analysis"`
+	Structured       *StructuredDiagnosis `json:"structured,omitempty"`
+	PromptTokens     int                  `json:"prompt_tokens,omitempty"`
+	CompletionTokens int                  `json:"completion_tokens,omitempty"`
+	CostUSD          float64              `json:"cost_usd,omitempty"`
 }
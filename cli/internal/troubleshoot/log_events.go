@@ -0,0 +1,78 @@
+package troubleshoot
+
+import "strings"
+
+// LogEvent is a single parsed log line, structured enough for SymptomChecker
+// to assert on fields/event names instead of doing substring matches against
+// the raw blob. See ParseStructured for how ts/level/event/fields are
+// extracted from JSON-per-line, logfmt, and plain console log lines alike.
+type LogEvent struct {
+	Timestamp string
+	Level     string
+	Event     string
+	Fields    map[string]string
+	CallID    string
+}
+
+// LogEvents is a parsed log with typed-predicate helpers, so analyze*
+// functions can ask "how many jitter.underflow events for this call" instead
+// of counting substring occurrences across the whole log blob.
+type LogEvents []LogEvent
+
+// ParseLogEvents splits logData into lines and parses each one via
+// ParseStructured. Lines that don't look like a log event at all (ok==false)
+// are skipped; callers that need the raw text for a substring fallback
+// should keep using the original logData, since ParseLogEvents is lossy by
+// design (only lines ParseStructured recognizes become LogEvents).
+func ParseLogEvents(logData string) LogEvents {
+	lines := strings.Split(logData, "\n")
+	events := make(LogEvents, 0, len(lines))
+	for _, line := range lines {
+		ts, level, event, fields, ok := ParseStructured(line)
+		if !ok {
+			continue
+		}
+		events = append(events, LogEvent{
+			Timestamp: ts,
+			Level:     level,
+			Event:     event,
+			Fields:    fields,
+			CallID:    fields["call_id"],
+		})
+	}
+	return events
+}
+
+// Where returns the subset of events matching pred.
+func (events LogEvents) Where(pred func(LogEvent) bool) LogEvents {
+	out := make(LogEvents, 0, len(events))
+	for _, e := range events {
+		if pred(e) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// CountEventContains returns how many events have substr in their Event
+// field (case-insensitive), e.g. CountEventContains("underflow").
+func (events LogEvents) CountEventContains(substr string) int {
+	substr = strings.ToLower(substr)
+	n := 0
+	for _, e := range events {
+		if strings.Contains(strings.ToLower(e.Event), substr) {
+			n++
+		}
+	}
+	return n
+}
+
+// ForCallID returns only the events whose CallID matches callID. An empty
+// callID matches everything (most troubleshoot log excerpts are already
+// scoped to a single call before this is called).
+func (events LogEvents) ForCallID(callID string) LogEvents {
+	if callID == "" {
+		return events
+	}
+	return events.Where(func(e LogEvent) bool { return e.CallID == callID })
+}
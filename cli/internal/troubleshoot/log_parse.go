@@ -51,6 +51,7 @@ func parseLogLine(line string) (level string, event string, fields map[string]st
 			event = v
 		}
 		fields = make(map[string]string, 16)
+		budget := defaultFlattenMaxFields
 		for k, v := range entry {
 			if k == "" || k == "event" || k == "level" {
 				continue
@@ -58,21 +59,26 @@ func parseLogLine(line string) (level string, event string, fields map[string]st
 			switch t := v.(type) {
 			case string:
 				fields[k] = t
+				budget--
 			case json.Number:
-				num := strings.TrimSpace(t.String())
-				if strings.Contains(num, ".") && !strings.ContainsAny(num, "eE") {
-					num = strings.TrimRight(num, "0")
-					num = strings.TrimRight(num, ".")
-				}
-				fields[k] = num
+				fields[k] = formatJSONNumber(t)
+				budget--
 			case bool:
 				if t {
 					fields[k] = "true"
 				} else {
 					fields[k] = "false"
 				}
-			default:
-				// Ignore nested objects; header and metrics should log flat fields.
+				budget--
+			case map[string]any, []any:
+				// Nested objects/arrays (tool call arguments, usage/metrics
+				// payloads) are flattened with dot/bracket keys instead of
+				// dropped, bounded by FlattenOptions' defaults so a
+				// pathological payload can't blow up memory.
+				flattenValue(k, t, 0, FlattenOptions{}.withDefaults(), fields, &budget)
+			}
+			if budget <= 0 {
+				break
 			}
 		}
 		return level, event, fields, true
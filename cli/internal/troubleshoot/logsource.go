@@ -0,0 +1,385 @@
+package troubleshoot
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LogSource abstracts where ai_engine log lines come from, so `agent rca`
+// can run against Docker, a bare-metal systemd unit, a Kubernetes pod, or an
+// archived log bundle without patching the binary. Select one with
+// RCA_LOG_SOURCE or --log-source; NewLogSourceFromEnv builds the configured
+// source.
+type LogSource interface {
+	// RecentLines returns the last `since` worth of log lines.
+	RecentLines(ctx context.Context, since time.Duration) (io.ReadCloser, error)
+	// Follow streams log lines as they're written.
+	Follow(ctx context.Context) (io.ReadCloser, error)
+}
+
+// NewLogSourceFromEnv builds the LogSource selected by --log-source
+// (flagValue takes priority) or RCA_LOG_SOURCE, defaulting to Docker (this
+// CLI's historical behavior). Recognized values: docker (default), file,
+// journald, kubectl; each reads its target from its own env var so operators
+// can point at their environment without patching the binary.
+func NewLogSourceFromEnv(flagValue string) LogSource {
+	source := strings.ToLower(strings.TrimSpace(flagValue))
+	if source == "" {
+		source = strings.ToLower(strings.TrimSpace(os.Getenv("RCA_LOG_SOURCE")))
+	}
+	switch source {
+	case "file":
+		return FileLogSource{Glob: os.Getenv("RCA_LOG_FILE_GLOB")}
+	case "journald":
+		return JournaldLogSource{Unit: os.Getenv("RCA_LOG_JOURNALD_UNIT")}
+	case "kubectl":
+		return KubectlLogSource{
+			Selector:  os.Getenv("RCA_LOG_KUBECTL_SELECTOR"),
+			Namespace: os.Getenv("RCA_LOG_KUBECTL_NAMESPACE"),
+		}
+	default:
+		return DockerLogSource{Container: os.Getenv("RCA_LOG_DOCKER_CONTAINER")}
+	}
+}
+
+// cmdReadCloser wraps a running command's stdout pipe so closing it also
+// reaps the child process, avoiding zombies when a --follow tail is stopped.
+type cmdReadCloser struct {
+	io.ReadCloser
+	cmd *exec.Cmd
+}
+
+func (c *cmdReadCloser) Close() error {
+	err := c.ReadCloser.Close()
+	_ = c.cmd.Wait()
+	return err
+}
+
+// DockerLogSource reads from a Docker container via `docker logs`, the
+// historical default for this CLI.
+type DockerLogSource struct {
+	Container string // default: "ai_engine"
+}
+
+func (d DockerLogSource) container() string {
+	if d.Container != "" {
+		return d.Container
+	}
+	return "ai_engine"
+}
+
+func (d DockerLogSource) RecentLines(ctx context.Context, since time.Duration) (io.ReadCloser, error) {
+	cmd := exec.CommandContext(ctx, "docker", "logs", "--since", formatDockerSince(since), d.container())
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("docker logs --since %s %s: %w", formatDockerSince(since), d.container(), err)
+	}
+	return io.NopCloser(strings.NewReader(string(out))), nil
+}
+
+func (d DockerLogSource) Follow(ctx context.Context) (io.ReadCloser, error) {
+	cmd := exec.CommandContext(ctx, "docker", "logs", "-f", d.container())
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	return &cmdReadCloser{ReadCloser: stdout, cmd: cmd}, nil
+}
+
+// FileLogSource reads from a glob of plain log files (e.g. rotated
+// ai_engine.log / ai_engine.log.1) for bare-metal/systemd deployments that
+// don't run ai_engine in Docker. since is honored by parsing the first
+// timestamp out of each line rather than relying on file mtimes, so an
+// archived log bundle with preserved timestamps still filters correctly.
+type FileLogSource struct {
+	Glob string // e.g. "/var/log/ai_engine/*.log"
+}
+
+func (f FileLogSource) matches() ([]string, error) {
+	paths, err := filepath.Glob(f.Glob)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+func (f FileLogSource) RecentLines(ctx context.Context, since time.Duration) (io.ReadCloser, error) {
+	paths, err := f.matches()
+	if err != nil {
+		return nil, err
+	}
+	cutoff := time.Now().Add(-since)
+	var out strings.Builder
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			if line == "" {
+				continue
+			}
+			if ts, ok := extractLineTimestamp(line); ok && ts.Before(cutoff) {
+				continue
+			}
+			out.WriteString(line)
+			out.WriteString("\n")
+		}
+	}
+	return io.NopCloser(strings.NewReader(out.String())), nil
+}
+
+func (f FileLogSource) Follow(ctx context.Context) (io.ReadCloser, error) {
+	paths, err := f.matches()
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no files match %s", f.Glob)
+	}
+	// Follow the most recent match; new rotations aren't picked up
+	// automatically — point Glob at the currently-active file for long runs.
+	file, err := os.Open(paths[len(paths)-1])
+	if err != nil {
+		return nil, err
+	}
+	if _, err := file.Seek(0, io.SeekEnd); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	pr, pw := io.Pipe()
+	lines := make(chan string, 256)
+	go followFile(ctx, file, lines)
+	go func() {
+		defer pw.Close()
+		for line := range lines {
+			if _, err := fmt.Fprintln(pw, line); err != nil {
+				return
+			}
+		}
+	}()
+	return pr, nil
+}
+
+// logTimestampPattern matches the first ISO-8601-ish timestamp anywhere in
+// a line so FileLogSource can honor `since` without relying on file mtimes.
+var logTimestampPattern = regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}`)
+
+func extractLineTimestamp(line string) (time.Time, bool) {
+	m := logTimestampPattern.FindString(line)
+	if m == "" {
+		return time.Time{}, false
+	}
+	t, err := time.Parse("2006-01-02T15:04:05", strings.Replace(m, " ", "T", 1))
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// JournaldLogSource reads from systemd's journal, for ai_engine running as a
+// systemd unit on bare metal rather than in a container.
+type JournaldLogSource struct {
+	Unit string // default: "ai_engine"
+}
+
+func (j JournaldLogSource) unit() string {
+	if j.Unit != "" {
+		return j.Unit
+	}
+	return "ai_engine"
+}
+
+func (j JournaldLogSource) RecentLines(ctx context.Context, since time.Duration) (io.ReadCloser, error) {
+	cmd := exec.CommandContext(ctx, "journalctl", "-u", j.unit(), "-o", "json", "--since", formatDockerSince(since), "--no-pager")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("journalctl -u %s --since %s: %w", j.unit(), formatDockerSince(since), err)
+	}
+	return io.NopCloser(strings.NewReader(extractJournaldMessages(out))), nil
+}
+
+func (j JournaldLogSource) Follow(ctx context.Context) (io.ReadCloser, error) {
+	cmd := exec.CommandContext(ctx, "journalctl", "-u", j.unit(), "-o", "json", "-f", "--no-pager")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		defer pw.Close()
+		defer cmd.Wait()
+		scanner := bufio.NewScanner(stdout)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			msg, ok := journaldMessage(scanner.Bytes())
+			if !ok {
+				continue
+			}
+			if _, err := fmt.Fprintln(pw, msg); err != nil {
+				return
+			}
+		}
+	}()
+	return pr, nil
+}
+
+// extractJournaldMessages pulls the MESSAGE field out of journalctl's
+// `-o json` envelope so downstream log parsing sees the same ai_engine log
+// lines it would from `docker logs`, not journald's wrapper JSON.
+func extractJournaldMessages(raw []byte) string {
+	var out strings.Builder
+	for _, line := range strings.Split(string(raw), "\n") {
+		if msg, ok := journaldMessage([]byte(line)); ok {
+			out.WriteString(msg)
+			out.WriteString("\n")
+		}
+	}
+	return out.String()
+}
+
+func journaldMessage(line []byte) (string, bool) {
+	if len(strings.TrimSpace(string(line))) == 0 {
+		return "", false
+	}
+	var entry struct {
+		Message string `json:"MESSAGE"`
+	}
+	if err := json.Unmarshal(line, &entry); err != nil || entry.Message == "" {
+		return "", false
+	}
+	return entry.Message, true
+}
+
+// KubectlLogSource reads from Kubernetes pods matching a label selector, for
+// ai_engine running as a Deployment/StatefulSet.
+type KubectlLogSource struct {
+	Selector  string // e.g. "app=ai_engine"
+	Namespace string // optional; empty uses kubectl's current context namespace
+}
+
+func (k KubectlLogSource) selector() string {
+	if k.Selector != "" {
+		return k.Selector
+	}
+	return "app=ai_engine"
+}
+
+func (k KubectlLogSource) args(extra ...string) []string {
+	args := []string{"logs", "-l", k.selector(), "--all-containers=true", "--prefix=true"}
+	if k.Namespace != "" {
+		args = append(args, "-n", k.Namespace)
+	}
+	return append(args, extra...)
+}
+
+func (k KubectlLogSource) RecentLines(ctx context.Context, since time.Duration) (io.ReadCloser, error) {
+	cmd := exec.CommandContext(ctx, "kubectl", k.args("--since="+formatDockerSince(since))...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("kubectl logs -l %s: %w", k.selector(), err)
+	}
+	return io.NopCloser(strings.NewReader(string(out))), nil
+}
+
+func (k KubectlLogSource) Follow(ctx context.Context) (io.ReadCloser, error) {
+	cmd := exec.CommandContext(ctx, "kubectl", k.args("-f")...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	return &cmdReadCloser{ReadCloser: stdout, cmd: cmd}, nil
+}
+
+// MultiLogSource concatenates several sources (e.g. one per ai_engine
+// replica in a distributed deployment) into a single stream, so `agent rca`
+// can triage across all of them in one pass.
+type MultiLogSource struct {
+	Sources []LogSource
+}
+
+func (m MultiLogSource) RecentLines(ctx context.Context, since time.Duration) (io.ReadCloser, error) {
+	var readers []io.Reader
+	var closers []io.Closer
+	for _, src := range m.Sources {
+		rc, err := src.RecentLines(ctx, since)
+		if err != nil {
+			continue
+		}
+		readers = append(readers, rc)
+		closers = append(closers, rc)
+	}
+	return &multiReadCloser{Reader: io.MultiReader(readers...), closers: closers}, nil
+}
+
+func (m MultiLogSource) Follow(ctx context.Context) (io.ReadCloser, error) {
+	pr, pw := io.Pipe()
+	var wg sync.WaitGroup
+	for _, src := range m.Sources {
+		rc, err := src.Follow(ctx)
+		if err != nil {
+			continue
+		}
+		wg.Add(1)
+		go func(rc io.ReadCloser) {
+			defer wg.Done()
+			defer rc.Close()
+			_, _ = io.Copy(pw, rc)
+		}(rc)
+	}
+	go func() {
+		wg.Wait()
+		pw.Close()
+	}()
+	return pr, nil
+}
+
+// multiReadCloser adapts an io.MultiReader over several sources' readers
+// into a single io.ReadCloser that closes all of them.
+type multiReadCloser struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (m *multiReadCloser) Close() error {
+	var firstErr error
+	for _, c := range m.closers {
+		if err := c.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// parseSinceDuration parses a `docker logs --since`-style duration string
+// (e.g. "24h", "72h") the way RCA_LOG_SINCE already does.
+func parseSinceDuration(since string) (time.Duration, error) {
+	d, err := time.ParseDuration(since)
+	if err != nil {
+		return 0, fmt.Errorf("invalid since duration %q: %w", since, err)
+	}
+	return d, nil
+}
@@ -0,0 +1,279 @@
+package troubleshoot
+
+import (
+	"encoding/binary"
+	"math"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Thresholds used to flag audio quality issues that can starve VAD/ASR.
+const (
+	minUsableLUFS   = -35.0 // below this, most VAD/ASR implementations miss speech
+	maxTruePeakDBTP = -1.0  // above this, the signal is clipping
+)
+
+// ExtractPCMDumpPaths returns the distinct pcm_dump_path values referenced by
+// "PROVIDER SEGMENT BYTES" and "Streaming segment bytes summary v2" events,
+// in the order first seen. Raw PCM dumps are opt-in, so an empty result is
+// the common case — AnalyzeLoudness skips cleanly when that happens.
+func ExtractPCMDumpPaths(logData string) []string {
+	seen := map[string]bool{}
+	var paths []string
+	for _, line := range strings.Split(logData, "\n") {
+		_, event, fields, ok := parseLogLine(line)
+		if !ok {
+			continue
+		}
+		if event != "PROVIDER SEGMENT BYTES" && event != "Streaming segment bytes summary v2" {
+			continue
+		}
+		p := strings.TrimSpace(fields["pcm_dump_path"])
+		if p == "" || seen[p] {
+			continue
+		}
+		seen[p] = true
+		paths = append(paths, p)
+	}
+	return paths
+}
+
+// AnalyzeLoudness computes integrated loudness (LUFS), true-peak, noise
+// floor, and clipping for a call, when a raw PCM dump sidecar is available.
+// It reads 16-bit signed little-endian mono PCM at runtime.UsedOutputSampleRateHz
+// and applies the ITU-R BS.1770 measurement algorithm. It leaves
+// metrics.LoudnessAnalyzed false and returns early when no dump path is
+// logged or the runtime sample rate is unknown — this is a best-effort
+// supplement to the byte/drift metrics above, not a required part of RCA.
+func AnalyzeLoudness(metrics *CallMetrics, logData string, runtime *ProviderRuntimeAudio) {
+	if runtime == nil || runtime.UsedOutputSampleRateHz <= 0 {
+		return
+	}
+	paths := ExtractPCMDumpPaths(logData)
+	if len(paths) == 0 {
+		return
+	}
+
+	var samples []int16
+	for _, p := range paths {
+		raw, err := os.ReadFile(p)
+		if err != nil {
+			continue
+		}
+		samples = append(samples, decodePCM16LE(raw)...)
+	}
+	if len(samples) == 0 {
+		return
+	}
+
+	sampleRate := runtime.UsedOutputSampleRateHz
+	floats := make([]float64, len(samples))
+	clipped := 0
+	for i, s := range samples {
+		if s == math.MaxInt16 || s == math.MinInt16 {
+			clipped++
+		}
+		floats[i] = float64(s) / 32768.0
+	}
+
+	weighted := applyKWeighting(floats, sampleRate)
+
+	metrics.LoudnessAnalyzed = true
+	metrics.IntegratedLUFS = gatedIntegratedLoudness(weighted, sampleRate)
+	metrics.TruePeakDBFS = estimateTruePeakDBFS(floats)
+	metrics.NoiseFloorDBFS = estimateNoiseFloorDBFS(floats, sampleRate)
+	metrics.ClippedSampleCount = clipped
+}
+
+func decodePCM16LE(raw []byte) []int16 {
+	n := len(raw) / 2
+	out := make([]int16, n)
+	for i := 0; i < n; i++ {
+		out[i] = int16(binary.LittleEndian.Uint16(raw[i*2 : i*2+2]))
+	}
+	return out
+}
+
+// applyKWeighting runs the ITU-R BS.1770 pre-filter: a high-shelf around
+// 1681 Hz followed by a high-pass around 38 Hz.
+func applyKWeighting(x []float64, sampleRate int) []float64 {
+	shelf := highShelfBiquad(sampleRate, 1681.0, 1.0, 4.0)
+	hp := highPassBiquad(sampleRate, 38.0, 0.5)
+	return hp.apply(shelf.apply(x))
+}
+
+type biquad struct {
+	b0, b1, b2, a1, a2 float64
+}
+
+func (f biquad) apply(x []float64) []float64 {
+	y := make([]float64, len(x))
+	var x1, x2, y1, y2 float64
+	for i, xn := range x {
+		yn := f.b0*xn + f.b1*x1 + f.b2*x2 - f.a1*y1 - f.a2*y2
+		y[i] = yn
+		x2, x1 = x1, xn
+		y2, y1 = y1, yn
+	}
+	return y
+}
+
+func highShelfBiquad(sampleRate int, freq, q, gainDB float64) biquad {
+	a := math.Pow(10, gainDB/40)
+	w0 := 2 * math.Pi * freq / float64(sampleRate)
+	cosw0, sinw0 := math.Cos(w0), math.Sin(w0)
+	alpha := sinw0 / (2 * q)
+	sq := 2 * math.Sqrt(a) * math.Sqrt(alpha)
+
+	b0 := a * ((a + 1) + (a-1)*cosw0 + sq)
+	b1 := -2 * a * ((a - 1) + (a+1)*cosw0)
+	b2 := a * ((a + 1) + (a-1)*cosw0 - sq)
+	a0 := (a + 1) - (a-1)*cosw0 + sq
+	a1 := 2 * ((a - 1) - (a+1)*cosw0)
+	a2 := (a + 1) - (a-1)*cosw0 - sq
+
+	return biquad{b0: b0 / a0, b1: b1 / a0, b2: b2 / a0, a1: a1 / a0, a2: a2 / a0}
+}
+
+func highPassBiquad(sampleRate int, freq, q float64) biquad {
+	w0 := 2 * math.Pi * freq / float64(sampleRate)
+	cosw0, sinw0 := math.Cos(w0), math.Sin(w0)
+	alpha := sinw0 / (2 * q)
+
+	b0 := (1 + cosw0) / 2
+	b1 := -(1 + cosw0)
+	b2 := (1 + cosw0) / 2
+	a0 := 1 + alpha
+	a1 := -2 * cosw0
+	a2 := 1 - alpha
+
+	return biquad{b0: b0 / a0, b1: b1 / a0, b2: b2 / a0, a1: a1 / a0, a2: a2 / a0}
+}
+
+const (
+	absoluteGateLUFS = -70.0
+	relativeGateLU   = -10.0
+)
+
+// gatedIntegratedLoudness computes 400ms blocks at 75% overlap, applies the
+// BS.1770 absolute + relative gates, and returns the gated mean in LUFS.
+func gatedIntegratedLoudness(weighted []float64, sampleRate int) float64 {
+	blockSize := sampleRate * 400 / 1000
+	hop := blockSize / 4 // 75% overlap
+	if blockSize <= 0 || hop <= 0 || len(weighted) < blockSize {
+		return meanSquareToLUFS(meanSquare(weighted))
+	}
+
+	var blockPower []float64
+	for start := 0; start+blockSize <= len(weighted); start += hop {
+		blockPower = append(blockPower, meanSquare(weighted[start:start+blockSize]))
+	}
+	if len(blockPower) == 0 {
+		return meanSquareToLUFS(meanSquare(weighted))
+	}
+
+	// Absolute gate: drop blocks quieter than -70 LUFS.
+	var absGated []float64
+	for _, p := range blockPower {
+		if meanSquareToLUFS(p) > absoluteGateLUFS {
+			absGated = append(absGated, p)
+		}
+	}
+	if len(absGated) == 0 {
+		return absoluteGateLUFS
+	}
+
+	// Relative gate: drop blocks more than 10 LU below the mean of the
+	// blocks that passed the absolute gate.
+	relativeThreshold := meanSquareToLUFS(average(absGated)) + relativeGateLU
+	var relGated []float64
+	for _, p := range absGated {
+		if meanSquareToLUFS(p) > relativeThreshold {
+			relGated = append(relGated, p)
+		}
+	}
+	if len(relGated) == 0 {
+		relGated = absGated
+	}
+
+	return meanSquareToLUFS(average(relGated))
+}
+
+func meanSquare(x []float64) float64 {
+	if len(x) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range x {
+		sum += v * v
+	}
+	return sum / float64(len(x))
+}
+
+func average(x []float64) float64 {
+	if len(x) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range x {
+		sum += v
+	}
+	return sum / float64(len(x))
+}
+
+func meanSquareToLUFS(ms float64) float64 {
+	if ms <= 0 {
+		return math.Inf(-1)
+	}
+	return -0.691 + 10*math.Log10(ms)
+}
+
+// estimateTruePeakDBFS approximates ITU-R BS.1770 true peak by 4x linear
+// oversampling (a lighter stand-in for a full polyphase FIR) and returns the
+// max absolute interpolated sample in dBFS.
+func estimateTruePeakDBFS(x []float64) float64 {
+	if len(x) == 0 {
+		return math.Inf(-1)
+	}
+	peak := 0.0
+	const oversample = 4
+	for i := 0; i < len(x)-1; i++ {
+		for k := 0; k < oversample; k++ {
+			frac := float64(k) / float64(oversample)
+			v := math.Abs(x[i] + (x[i+1]-x[i])*frac)
+			if v > peak {
+				peak = v
+			}
+		}
+	}
+	if peak == 0 {
+		return math.Inf(-1)
+	}
+	return 20 * math.Log10(peak)
+}
+
+// estimateNoiseFloorDBFS takes the 10th-percentile short-term RMS across
+// 20ms frames as an estimate of the noise floor. A full implementation would
+// restrict this to the silence regions bounded by VAD gate closures; scoring
+// all frames is a simpler approximation that still tracks background noise
+// well for calls that are mostly silence between turns.
+func estimateNoiseFloorDBFS(x []float64, sampleRate int) float64 {
+	frameSize := sampleRate * 20 / 1000
+	if frameSize <= 0 || len(x) < frameSize {
+		return math.Inf(-1)
+	}
+	var rmsValues []float64
+	for start := 0; start+frameSize <= len(x); start += frameSize {
+		rmsValues = append(rmsValues, math.Sqrt(meanSquare(x[start:start+frameSize])))
+	}
+	if len(rmsValues) == 0 {
+		return math.Inf(-1)
+	}
+	sort.Float64s(rmsValues)
+	floor := rmsValues[len(rmsValues)/10]
+	if floor <= 0 {
+		return math.Inf(-1)
+	}
+	return 20 * math.Log10(floor)
+}
@@ -3,6 +3,7 @@ package troubleshoot
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
 )
@@ -31,6 +32,12 @@ type CallMetrics struct {
 	ProviderOutputFormat string
 	SampleRate           int
 
+	// ExternalMedia RTP transport observations (from logs), used to
+	// cross-check RCAHeader.ExternalMediaCodec in AnalyzeFormatAlignment.
+	ExternalMediaObservedPayloadBytes int
+	ExternalMediaObservedRTPClockHz   int
+	ExternalMediaSDPConnectionHost    string
+
 	// Format alignment (from config + logs)
 	FormatAlignment *FormatAlignment
 
@@ -39,6 +46,18 @@ type CallMetrics struct {
 
 	// Configuration issues
 	ConfigErrors []string
+
+	// Loudness/level analysis (optional — only populated when a PCM dump
+	// sidecar is available; see AnalyzeLoudness in loudness.go)
+	LoudnessAnalyzed   bool
+	IntegratedLUFS     float64
+	TruePeakDBFS       float64
+	NoiseFloorDBFS     float64
+	ClippedSampleCount int
+
+	// MOS is an E-model-style estimated Mean Opinion Score (1-5), set by
+	// computeMOS alongside the quality score in computeCallQuality.
+	MOS float64
 }
 
 // FormatAlignment tracks format/sampling configuration and actual behavior
@@ -50,6 +69,11 @@ type FormatAlignment struct {
 	ConfigProviderOutputFormat string
 	ConfigSampleRate           int
 
+	// Active wizard.PipelineProfile this call was validated against, if any.
+	ProfileName     string
+	ProfileChannels int
+	ProfileBitDepth int
+
 	// From runtime logs
 	RuntimeAudioSocketFormat   string
 	RuntimeProviderInputFormat string
@@ -65,8 +89,39 @@ type FormatAlignment struct {
 	SampleRateMismatch     bool
 	FrameSizeMismatch      bool
 
+	// Ring-buffer-derived cadence (see trace_alignment.go), populated only
+	// when the call's logs include a RCA_TRACE_SUMMARY line.
+	TraceObservedFrameHz float64
+	PtimeDriftDetected   bool
+
+	// ManifestURL points at the HLS replay manifest for this call (see
+	// troubleshoot/replay), populated only when --replay-dir was set and a
+	// --capture pcap was available to build it from.
+	ManifestURL string
+
 	// Detailed issues
 	Issues []string
+
+	// ExternalMedia-specific checks (see validateExternalMediaCodec in
+	// format_analyzer.go), carried as stable-coded issues alongside Issues
+	// so LLM prompts and dashboards can key off a specific failure mode.
+	ExternalMediaCodedIssues []FormatIssue
+}
+
+// FormatIssueCode identifies a specific ExternalMedia format failure mode.
+type FormatIssueCode string
+
+const (
+	IssueExternalMediaPayloadSizeMismatch FormatIssueCode = "EXTERNALMEDIA_PAYLOAD_SIZE_MISMATCH"
+	IssueExternalMediaClockMismatch       FormatIssueCode = "EXTERNALMEDIA_RTP_CLOCK_MISMATCH"
+	IssueExternalMediaHostMismatch        FormatIssueCode = "EXTERNALMEDIA_ADVERTISE_HOST_MISMATCH"
+)
+
+// FormatIssue is a structured, stably-coded counterpart to a FormatAlignment
+// Issues[] string.
+type FormatIssue struct {
+	Code    FormatIssueCode `json:"code"`
+	Message string          `json:"message"`
 }
 
 // ProviderSegment tracks provider bytes per segment
@@ -275,6 +330,15 @@ func extractTransportAlignmentFields(fields map[string]string, metrics *CallMetr
 	if v := fields["sample_rate"]; v != "" {
 		metrics.SampleRate = atoiSafe(v)
 	}
+	if v := fields["external_media_payload_bytes"]; v != "" {
+		metrics.ExternalMediaObservedPayloadBytes = atoiSafe(v)
+	}
+	if v := fields["external_media_rtp_clock_hz"]; v != "" {
+		metrics.ExternalMediaObservedRTPClockHz = atoiSafe(v)
+	}
+	if v := fields["sdp_connection_host"]; v != "" {
+		metrics.ExternalMediaSDPConnectionHost = v
+	}
 }
 
 func extractVADSettingsFields(fields map[string]string, metrics *CallMetrics) {
@@ -417,6 +481,18 @@ func extractTransportAlignment(entry map[string]interface{}, metrics *CallMetric
 	if sr, ok := entry["sample_rate"].(float64); ok {
 		metrics.SampleRate = int(sr)
 	}
+
+	if pb, ok := entry["external_media_payload_bytes"].(float64); ok {
+		metrics.ExternalMediaObservedPayloadBytes = int(pb)
+	}
+
+	if clk, ok := entry["external_media_rtp_clock_hz"].(float64); ok {
+		metrics.ExternalMediaObservedRTPClockHz = int(clk)
+	}
+
+	if host, ok := entry["sdp_connection_host"].(string); ok {
+		metrics.ExternalMediaSDPConnectionHost = host
+	}
 }
 
 func extractVADSettings(entry map[string]interface{}, metrics *CallMetrics) {
@@ -436,6 +512,54 @@ func abs(x float64) float64 {
 	return x
 }
 
+// computeMOS estimates a MOS (1-5) for the call using an E-model-style
+// R-factor mapping. ai_engine doesn't log a measured one-way delay or a
+// real packet-loss fraction, so this uses the closest available signals:
+// the configured jitter buffer target (header.StreamingJitterBufferMs) as
+// bufferDelayMs, and the jitter-buffer underflow rate as a loss proxy.
+func computeMOS(metrics *CallMetrics, header *RCAHeader) float64 {
+	bufferDelayMs := 0.0
+	if header != nil {
+		bufferDelayMs = float64(header.StreamingJitterBufferMs)
+	}
+
+	lossFraction := 0.0
+	if metrics.UnderflowCount > 0 && len(metrics.StreamingSummaries) > 0 {
+		totalFrames := 0
+		for _, seg := range metrics.StreamingSummaries {
+			totalFrames += seg.BytesSent / 320
+		}
+		if totalFrames > 0 {
+			lossFraction = float64(metrics.UnderflowCount) / float64(totalFrames)
+		}
+	}
+
+	r := 93.0
+
+	id := 0.024 * bufferDelayMs
+	if excess := bufferDelayMs - 177.3; excess > 0 {
+		id += 0.11 * excess
+	}
+	r -= id
+
+	ie := 30.0 * math.Log(1+15*lossFraction)
+	r -= ie
+
+	if r < 0 {
+		r = 0
+	} else if r > 100 {
+		r = 100
+	}
+
+	mos := 1 + 0.035*r + 7e-6*r*(r-60)*(100-r)
+	if mos < 1 {
+		mos = 1
+	} else if mos > 4.5 {
+		mos = 4.5
+	}
+	return mos
+}
+
 // FormatMetricsForLLM formats metrics into human-readable text for LLM prompt
 func (m *CallMetrics) FormatForLLM() string {
 	var out strings.Builder
@@ -534,6 +658,33 @@ func (m *CallMetrics) FormatForLLM() string {
 		out.WriteString("\n")
 	}
 
+	// Loudness/level analysis
+	if m.LoudnessAnalyzed {
+		out.WriteString("Loudness/Level Analysis:\n")
+		out.WriteString(fmt.Sprintf("  Integrated loudness: %.1f LUFS\n", m.IntegratedLUFS))
+		out.WriteString(fmt.Sprintf("  True peak: %.1f dBTP\n", m.TruePeakDBFS))
+		out.WriteString(fmt.Sprintf("  Noise floor: %.1f dBFS\n", m.NoiseFloorDBFS))
+
+		if m.IntegratedLUFS < minUsableLUFS {
+			out.WriteString(fmt.Sprintf("  ‚ö†Ô∏è  ISSUE: Audio too quiet for reliable VAD/ASR (< %.0f LUFS)\n", minUsableLUFS))
+		}
+		if m.TruePeakDBFS > maxTruePeakDBTP {
+			out.WriteString(fmt.Sprintf("  ‚ö†Ô∏è  ISSUE: Audio is clipping (true peak > %.0f dBTP)\n", maxTruePeakDBTP))
+		}
+		if m.ClippedSampleCount > 0 {
+			out.WriteString(fmt.Sprintf("  ‚ö†Ô∏è  %d clipped samples detected\n", m.ClippedSampleCount))
+		}
+		if m.VADSettings != nil && m.VADSettings.EnergyThreshold > 0 {
+			// EnergyThreshold is configured in raw 16-bit PCM amplitude units;
+			// convert to dBFS for an apples-to-apples noise floor comparison.
+			energyThresholdDBFS := 20 * math.Log10(float64(m.VADSettings.EnergyThreshold)/32768.0)
+			if m.NoiseFloorDBFS > energyThresholdDBFS {
+				out.WriteString(fmt.Sprintf("  ‚ö†Ô∏è  ISSUE: Noise floor (%.1f dBFS) is above the VAD energy threshold (%.1f dBFS)\n", m.NoiseFloorDBFS, energyThresholdDBFS))
+			}
+		}
+		out.WriteString("\n")
+	}
+
 	// Config errors
 	if len(m.ConfigErrors) > 0 {
 		out.WriteString("Configuration Errors:\n")
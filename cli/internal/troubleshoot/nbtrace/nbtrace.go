@@ -0,0 +1,277 @@
+// Package nbtrace captures per-frame audio pipeline events (capture,
+// provider push/pull, VAD decisions, TTS emit, barge-in protection) into
+// bounded, lock-free ring buffers per call, so the data is available for
+// RCA once the call ends. Ring.Push itself never blocks or allocates;
+// Record also takes a short registry lock around the per-call map lookup
+// (see Record). Modeled on NBLog from Android's frameworks_av.
+//
+// This package is the producer/consumer library only: ai_engine's audio
+// threads are expected to call Record on the hot path and DrainTrace once
+// per call at hangup, then log the result so the troubleshoot package's
+// log-driven RCA pipeline can cross-check it (see
+// extractTraceSummary/AnalyzeFormatAlignment in package troubleshoot) —
+// RCA itself never talks to these rings directly.
+package nbtrace
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// EventID identifies the kind of pipeline event recorded in a Slot.
+type EventID uint8
+
+const (
+	EventCapture EventID = iota
+	EventProviderPush
+	EventVADDecision
+	EventTTSEmit
+	EventBargeInStart
+	EventBargeInEnd
+)
+
+// Slot is one fixed-size ring buffer entry: a monotonic timestamp, the
+// event kind, and up to 4 uint32 payload words (frame size, sequence
+// number, etc.) — sized so a Push is a single struct copy, never an
+// allocation.
+type Slot struct {
+	TimestampNs int64
+	Event       EventID
+	Payload     [4]uint32
+}
+
+// ringCapacity is the number of slots per ring. Must be a power of two so
+// index wrapping is a mask instead of a modulo.
+const ringCapacity = 4096
+
+// Ring is a single-producer single-consumer lock-free ring buffer of Slot.
+// The producer (the audio thread) calls Push and never blocks; if the
+// consumer falls more than ringCapacity slots behind, Drain detects the
+// overrun from the sequence counters rather than the producer stalling or
+// dropping silently.
+type Ring struct {
+	slots    [ringCapacity]Slot
+	writeSeq uint64 // advanced by the single producer after each Push
+	readSeq  uint64 // only touched by the single consumer, from Drain
+}
+
+// NewRing returns an empty ring ready for Push/Drain.
+func NewRing() *Ring {
+	return &Ring{}
+}
+
+// Push records one event. Never blocks and never allocates.
+func (r *Ring) Push(tsNs int64, event EventID, payload [4]uint32) {
+	seq := atomic.LoadUint64(&r.writeSeq)
+	r.slots[seq&(ringCapacity-1)] = Slot{TimestampNs: tsNs, Event: event, Payload: payload}
+	atomic.StoreUint64(&r.writeSeq, seq+1)
+}
+
+// Drain returns every slot written since the last Drain, plus how many
+// slots were lost to overrun (the producer wrapped past them before this
+// call). Only the consumer may call Drain.
+func (r *Ring) Drain() (slots []Slot, overrun int) {
+	writeSeq := atomic.LoadUint64(&r.writeSeq)
+	readSeq := r.readSeq
+
+	if pending := writeSeq - readSeq; pending > ringCapacity {
+		overrun = int(pending - ringCapacity)
+		readSeq = writeSeq - ringCapacity
+	}
+
+	slots = make([]Slot, 0, writeSeq-readSeq)
+	for seq := readSeq; seq != writeSeq; seq++ {
+		slots = append(slots, r.slots[seq&(ringCapacity-1)])
+	}
+	r.readSeq = writeSeq
+	return slots, overrun
+}
+
+// Stage identifies which per-call ring an event belongs to.
+type Stage int
+
+const (
+	StageCapture Stage = iota
+	StageProviderIn
+	StageProviderOut
+	StagePlayback
+	numStages
+)
+
+// callTrace holds one Ring per pipeline stage for a single call.
+type callTrace struct {
+	rings [numStages]*Ring
+}
+
+func newCallTrace() *callTrace {
+	ct := &callTrace{}
+	for i := range ct.rings {
+		ct.rings[i] = NewRing()
+	}
+	return ct
+}
+
+var (
+	registryMu sync.Mutex
+	registry   = make(map[string]*callTrace)
+)
+
+// Record pushes one event onto callID's ring for stage, registering callID
+// on first use. Every Record takes the registry lock around the map lookup
+// (and, on first use, the allocation of the call's rings), so concurrent
+// Record and DrainTrace calls briefly contend on it; the Push onto the ring
+// itself takes no lock.
+func Record(callID string, stage Stage, tsNs int64, event EventID, payload [4]uint32) {
+	registryMu.Lock()
+	ct, ok := registry[callID]
+	if !ok {
+		ct = newCallTrace()
+		registry[callID] = ct
+	}
+	registryMu.Unlock()
+	ct.rings[stage].Push(tsNs, event, payload)
+}
+
+// Histogram is a p50/p95/p99 latency/jitter summary in milliseconds,
+// computed once at DrainTrace time.
+type Histogram struct {
+	P50Ms float64
+	P95Ms float64
+	P99Ms float64
+	N     int
+}
+
+// TraceSummary is what DrainTrace returns: capture-stage inter-frame jitter,
+// provider round-trip time, VAD-to-barge-in latency, and the observed
+// frame cadence (frames/sec) derived from capture-stage timestamps.
+type TraceSummary struct {
+	CaptureJitter    Histogram
+	ProviderRTT      Histogram
+	VADToBargeIn     Histogram
+	ObservedFrameHz  float64
+	OverrunsDetected int
+}
+
+// DrainTrace flushes every stage's ring for callID, aggregates the
+// histograms, and forgets callID — a call is drained exactly once, at call
+// end. It holds the registry lock only for the map lookup and delete, so a
+// Record running on the audio thread waits at most for that; each
+// Ring.Drain is itself lock-free.
+func DrainTrace(callID string) TraceSummary {
+	registryMu.Lock()
+	ct, ok := registry[callID]
+	delete(registry, callID)
+	registryMu.Unlock()
+	if !ok {
+		return TraceSummary{}
+	}
+
+	captureSlots, o1 := ct.rings[StageCapture].Drain()
+	inSlots, o2 := ct.rings[StageProviderIn].Drain()
+	outSlots, o3 := ct.rings[StageProviderOut].Drain()
+	playbackSlots, o4 := ct.rings[StagePlayback].Drain()
+
+	return TraceSummary{
+		CaptureJitter:    interFrameJitter(captureSlots),
+		ProviderRTT:      pairLatency(inSlots, outSlots),
+		VADToBargeIn:     eventLatency(mergeSlots(inSlots, playbackSlots), EventVADDecision, EventBargeInStart),
+		ObservedFrameHz:  observedFrameRate(captureSlots),
+		OverrunsDetected: o1 + o2 + o3 + o4,
+	}
+}
+
+func histogramFromDeltasMs(deltas []float64) Histogram {
+	if len(deltas) == 0 {
+		return Histogram{}
+	}
+	sort.Float64s(deltas)
+	return Histogram{
+		P50Ms: percentile(deltas, 50),
+		P95Ms: percentile(deltas, 95),
+		P99Ms: percentile(deltas, 99),
+		N:     len(deltas),
+	}
+}
+
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(p / 100 * float64(len(sorted)-1))
+	return sorted[idx]
+}
+
+// interFrameJitter histograms the gaps between consecutive slots in a
+// single stage's stream — the "inter-frame jitter" for that stage.
+func interFrameJitter(slots []Slot) Histogram {
+	if len(slots) < 2 {
+		return Histogram{}
+	}
+	deltas := make([]float64, 0, len(slots)-1)
+	for i := 1; i < len(slots); i++ {
+		deltas = append(deltas, float64(slots[i].TimestampNs-slots[i-1].TimestampNs)/1e6)
+	}
+	return histogramFromDeltasMs(deltas)
+}
+
+// observedFrameRate estimates frames/sec from the span between the first
+// and last slot, used to cross-check the configured ptime.
+func observedFrameRate(slots []Slot) float64 {
+	if len(slots) < 2 {
+		return 0
+	}
+	spanNs := slots[len(slots)-1].TimestampNs - slots[0].TimestampNs
+	if spanNs <= 0 {
+		return 0
+	}
+	return float64(len(slots)-1) / (float64(spanNs) / 1e9)
+}
+
+// pairLatency matches each in-slot to the out-slot sharing the same
+// Payload[0] sequence number and histograms the gap between them — the
+// producer is expected to reuse a monotonically increasing sequence number
+// as Payload[0] for exactly this purpose. Slots with no match are skipped.
+func pairLatency(inSlots, outSlots []Slot) Histogram {
+	outBySeq := make(map[uint32]int64, len(outSlots))
+	for _, s := range outSlots {
+		outBySeq[s.Payload[0]] = s.TimestampNs
+	}
+	var deltas []float64
+	for _, s := range inSlots {
+		if outTs, ok := outBySeq[s.Payload[0]]; ok {
+			deltas = append(deltas, float64(outTs-s.TimestampNs)/1e6)
+		}
+	}
+	return histogramFromDeltasMs(deltas)
+}
+
+// eventLatency histograms the gap from each fromEvent to the next toEvent
+// in a timestamp-ordered slot stream, skipping unmatched events.
+func eventLatency(slots []Slot, fromEvent, toEvent EventID) Histogram {
+	var deltas []float64
+	pending := int64(-1)
+	for _, s := range slots {
+		switch s.Event {
+		case fromEvent:
+			pending = s.TimestampNs
+		case toEvent:
+			if pending >= 0 {
+				deltas = append(deltas, float64(s.TimestampNs-pending)/1e6)
+				pending = -1
+			}
+		}
+	}
+	return histogramFromDeltasMs(deltas)
+}
+
+// mergeSlots timestamp-sorts two stages' slots together so cross-stage
+// event pairs (e.g. a VAD decision in StageProviderIn followed by a
+// barge-in start in StagePlayback) can be read as one ordered stream.
+func mergeSlots(a, b []Slot) []Slot {
+	merged := make([]Slot, 0, len(a)+len(b))
+	merged = append(merged, a...)
+	merged = append(merged, b...)
+	sort.Slice(merged, func(i, j int) bool { return merged[i].TimestampNs < merged[j].TimestampNs })
+	return merged
+}
@@ -0,0 +1,109 @@
+package nbtrace
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestRingConcurrentProducerConsumer(t *testing.T) {
+	t.Parallel()
+
+	const produced = 10_000
+	r := NewRing()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < produced; i++ {
+			r.Push(int64(i), EventCapture, [4]uint32{uint32(i)})
+		}
+	}()
+
+	var (
+		collected []Slot
+		overruns  int
+	)
+	for len(collected)+overruns < produced {
+		slots, o := r.Drain()
+		collected = append(collected, slots...)
+		overruns += o
+	}
+	wg.Wait()
+
+	// Drain one more time in case the last Push landed after the loop's
+	// final check but before the producer goroutine finished.
+	slots, o := r.Drain()
+	collected = append(collected, slots...)
+	overruns += o
+
+	if got := len(collected) + overruns; got != produced {
+		t.Fatalf("collected+overruns = %d, want %d", got, produced)
+	}
+
+	// With no overrun, every collected slot's sequence payload must be
+	// strictly increasing (Push is single-producer, so order is
+	// preserved) and none skipped.
+	if overruns == 0 {
+		for i, s := range collected {
+			if int(s.Payload[0]) != i {
+				t.Fatalf("collected[%d].Payload[0] = %d, want %d (sequence gap or reorder)", i, s.Payload[0], i)
+			}
+		}
+	}
+}
+
+func TestPercentileEdgeCases(t *testing.T) {
+	t.Parallel()
+
+	if got := percentile(nil, 50); got != 0 {
+		t.Fatalf("percentile(nil, 50) = %v, want 0", got)
+	}
+	if got := percentile([]float64{42}, 99); got != 42 {
+		t.Fatalf("percentile(single-element, 99) = %v, want 42", got)
+	}
+	sorted := []float64{1, 2, 3, 4, 5}
+	if got := percentile(sorted, 0); got != 1 {
+		t.Fatalf("percentile(sorted, 0) = %v, want 1 (first element)", got)
+	}
+	if got := percentile(sorted, 100); got != 5 {
+		t.Fatalf("percentile(sorted, 100) = %v, want 5 (last element)", got)
+	}
+}
+
+func TestPairLatencySkipsUnmatchedSlots(t *testing.T) {
+	t.Parallel()
+
+	in := []Slot{
+		{TimestampNs: 0, Payload: [4]uint32{1}},
+		{TimestampNs: 100, Payload: [4]uint32{2}}, // no matching out-slot below
+	}
+	out := []Slot{
+		{TimestampNs: 5_000_000, Payload: [4]uint32{1}}, // 5ms after in-slot 1
+	}
+
+	hist := pairLatency(in, out)
+	if hist.N != 1 {
+		t.Fatalf("expected 1 matched pair, got N=%d", hist.N)
+	}
+	if hist.P50Ms != 5 {
+		t.Fatalf("P50Ms = %v, want 5", hist.P50Ms)
+	}
+}
+
+func TestEventLatencySkipsUnmatchedEvents(t *testing.T) {
+	t.Parallel()
+
+	slots := []Slot{
+		{TimestampNs: 0, Event: EventVADDecision},
+		{TimestampNs: 2_000_000, Event: EventBargeInStart}, // 2ms gap, matched
+		{TimestampNs: 3_000_000, Event: EventBargeInStart}, // no preceding VAD, skipped
+	}
+	hist := eventLatency(slots, EventVADDecision, EventBargeInStart)
+	if hist.N != 1 {
+		t.Fatalf("expected 1 matched event pair, got N=%d", hist.N)
+	}
+	if hist.P50Ms != 2 {
+		t.Fatalf("P50Ms = %v, want 2", hist.P50Ms)
+	}
+}
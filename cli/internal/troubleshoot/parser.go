@@ -0,0 +1,107 @@
+package troubleshoot
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io"
+)
+
+// Entry is one successfully parsed log line, as ParseStructured sees it,
+// plus the line number it came from.
+type Entry struct {
+	LineNo int
+	Time   string // leading timestamp, if any (see ParseStructured)
+	Level  string
+	Event  string
+	Fields map[string]string
+}
+
+// gzipMagic is the two leading bytes of a gzip stream (RFC 1952).
+var gzipMagic = [2]byte{0x1f, 0x8b}
+
+// Parser reads log lines from an io.Reader one at a time (rather than
+// requiring the whole file as a string, as ExtractToolCalls and friends
+// have always assumed), so a multi-GB rotated Asterisk log can be
+// streamed through with bounded memory. A .log.gz stream is detected by
+// its magic bytes and transparently decompressed.
+type Parser struct {
+	scanner *bufio.Scanner
+	lineNo  int
+	filters []func(Entry) bool
+}
+
+// NewParser wraps r in a line scanner with a raised buffer (log lines
+// carrying a large JSON payload can exceed bufio.Scanner's 64KB default),
+// auto-detecting gzip compression by magic bytes.
+func NewParser(r io.Reader) *Parser {
+	br := bufio.NewReader(r)
+	if magic, err := br.Peek(2); err == nil && len(magic) == 2 && magic[0] == gzipMagic[0] && magic[1] == gzipMagic[1] {
+		if gz, err := gzip.NewReader(br); err == nil {
+			r = gz
+		}
+	} else {
+		r = br
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	return &Parser{scanner: scanner}
+}
+
+// Filter adds a predicate every entry must satisfy to be returned from
+// Next/CollectToolCalls; filters are checked in the order added and short-
+// circuit on the first failure. Returns the Parser for chaining, e.g.
+// p.Filter(func(e Entry) bool { return e.Level == "error" }).Next().
+func (p *Parser) Filter(fn func(Entry) bool) *Parser {
+	p.filters = append(p.filters, fn)
+	return p
+}
+
+func (p *Parser) passesFilters(e Entry) bool {
+	for _, fn := range p.filters {
+		if !fn(e) {
+			return false
+		}
+	}
+	return true
+}
+
+// next is the internal, error-swallowing iterator used by Next and
+// CollectToolCalls: it returns ok=false both at EOF and once the
+// underlying scanner reports an error (surfaced separately via Next).
+func (p *Parser) next() (Entry, bool) {
+	for p.scanner.Scan() {
+		p.lineNo++
+		line := p.scanner.Text()
+		ts, level, event, fields, ok := ParseStructured(line)
+		if !ok {
+			continue
+		}
+		entry := Entry{LineNo: p.lineNo, Time: ts, Level: level, Event: event, Fields: fields}
+		if !p.passesFilters(entry) {
+			continue
+		}
+		return entry, true
+	}
+	return Entry{}, false
+}
+
+// Next returns the next entry that parses and passes every Filter, or
+// io.EOF once the reader is exhausted (or the underlying scan failed,
+// e.g. a line exceeding the raised buffer).
+func (p *Parser) Next() (Entry, error) {
+	if entry, ok := p.next(); ok {
+		return entry, nil
+	}
+	if err := p.scanner.Err(); err != nil {
+		return Entry{}, err
+	}
+	return Entry{}, io.EOF
+}
+
+// CollectToolCalls drains the parser into tool-call records, the same
+// correlation logic ExtractToolCalls uses, but streaming line-by-line
+// instead of requiring the whole log as a string.
+func (p *Parser) CollectToolCalls() []ToolCallRecord {
+	return collectToolCalls(p.next, ExtractOptions{})
+}
@@ -0,0 +1,92 @@
+package troubleshoot
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestParserNextYieldsEntriesInOrder(t *testing.T) {
+	t.Parallel()
+
+	data := "" +
+		`{"event":"a","level":"info"}` + "\n" +
+		`{"event":"b","level":"error"}` + "\n"
+
+	p := NewParser(strings.NewReader(data))
+	first, err := p.Next()
+	if err != nil {
+		t.Fatalf("first Next: %v", err)
+	}
+	if first.Event != "a" || first.LineNo != 1 {
+		t.Fatalf("first=%+v", first)
+	}
+
+	second, err := p.Next()
+	if err != nil {
+		t.Fatalf("second Next: %v", err)
+	}
+	if second.Event != "b" || second.LineNo != 2 {
+		t.Fatalf("second=%+v", second)
+	}
+
+	if _, err := p.Next(); err != io.EOF {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+}
+
+func TestParserFilter(t *testing.T) {
+	t.Parallel()
+
+	data := "" +
+		`{"event":"a","level":"info"}` + "\n" +
+		`{"event":"b","level":"error"}` + "\n"
+
+	p := NewParser(strings.NewReader(data)).Filter(func(e Entry) bool { return e.Level == "error" })
+	entry, err := p.Next()
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	if entry.Event != "b" {
+		t.Fatalf("expected the error-level entry, got %+v", entry)
+	}
+	if _, err := p.Next(); err != io.EOF {
+		t.Fatalf("expected io.EOF after the only matching entry, got %v", err)
+	}
+}
+
+func TestParserAutoDetectsGzip(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	gz.Write([]byte(`{"event":"zipped","level":"info"}` + "\n"))
+	gz.Close()
+
+	p := NewParser(&buf)
+	entry, err := p.Next()
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	if entry.Event != "zipped" {
+		t.Fatalf("event=%q", entry.Event)
+	}
+}
+
+func TestParserCollectToolCalls(t *testing.T) {
+	t.Parallel()
+
+	data := "" +
+		"[info     ] tool call: check_status(foo) [src] function_call_id=1\n" +
+		"[info     ] Tool check_status executed: success [src] function_call_id=1 message=ok\n"
+
+	calls := NewParser(strings.NewReader(data)).CollectToolCalls()
+	if len(calls) != 1 {
+		t.Fatalf("expected 1 tool call, got %d", len(calls))
+	}
+	if calls[0].Status != "success" || calls[0].Message != "ok" {
+		t.Fatalf("call=%+v", calls[0])
+	}
+}
@@ -0,0 +1,304 @@
+package troubleshoot
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Result is one path match against a parsed log line.
+type Result struct {
+	Line  int               // 1-based source line number
+	Value string            // the matched field's value
+	Entry map[string]string // the full flattened field map for that line (event/level included)
+}
+
+// Query evaluates path against every line in lines (as produced by
+// strings.Split(logData, "\n") or similar) and returns every match, in
+// line order. See QueryReader's doc comment for the path syntax.
+func Query(lines []string, path string) ([]Result, error) {
+	segments, err := parseQueryPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []Result
+	for i, line := range lines {
+		_, level, event, fields, ok := ParseStructured(line)
+		if !ok {
+			continue
+		}
+		entry := cloneFields(fields)
+		entry["event"] = event
+		entry["level"] = level
+
+		for _, key := range evalQueryPath(segments, entry) {
+			value, present := entry[key]
+			if !present {
+				continue
+			}
+			results = append(results, Result{Line: i + 1, Value: value, Entry: entry})
+		}
+	}
+	return results, nil
+}
+
+// QueryReader is Query, but reading lines from r instead of a pre-split
+// slice - the form the `rca grep` CLI command and any future streaming
+// caller use.
+func QueryReader(r io.Reader, path string) ([]Result, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return Query(lines, path)
+}
+
+// QueryScalar returns the first match's value as a plain string - the
+// convenience form for callers who just want one fact (e.g. "the call_id
+// of the first error tool_call") rather than every match.
+func QueryScalar(lines []string, path string) (string, bool, error) {
+	results, err := Query(lines, path)
+	if err != nil {
+		return "", false, err
+	}
+	if len(results) == 0 {
+		return "", false, nil
+	}
+	return results[0].Value, true, nil
+}
+
+func cloneFields(fields map[string]string) map[string]string {
+	out := make(map[string]string, len(fields)+2)
+	for k, v := range fields {
+		out[k] = v
+	}
+	return out
+}
+
+// querySegment is one dot-separated path component: either a plain key
+// (possibly an array field name like "metrics") or a "#(expr)" filter
+// clause.
+type querySegment struct {
+	isFilter bool
+	key      string      // set when !isFilter
+	filter   *queryFilter // set when isFilter
+}
+
+// parseQueryPath splits path on '.' while keeping "#(...)" clauses (which
+// may themselves contain '.'-free comparisons) intact, e.g.
+// `#(event=="tool_call" && level=="error").call_id` becomes
+// [{filter: event=="tool_call" && level=="error"}, {key: "call_id"}], and
+// `metrics.#(name=="latency_ms").value` becomes
+// [{key: "metrics"}, {filter: name=="latency_ms"}, {key: "value"}].
+func parseQueryPath(path string) ([]querySegment, error) {
+	var segments []querySegment
+	i, n := 0, len(path)
+	for i < n {
+		if path[i] == '.' {
+			i++
+			continue
+		}
+		if path[i] == '#' && i+1 < n && path[i+1] == '(' {
+			depth := 0
+			start := i + 1
+			j := start
+			for ; j < n; j++ {
+				switch path[j] {
+				case '(':
+					depth++
+				case ')':
+					depth--
+					if depth == 0 {
+						j++
+						goto closed
+					}
+				}
+			}
+			return nil, fmt.Errorf("unterminated #(...) filter in path %q", path)
+		closed:
+			expr := path[start+1 : j-1]
+			f, err := parseQueryFilter(expr)
+			if err != nil {
+				return nil, fmt.Errorf("path %q: %w", path, err)
+			}
+			segments = append(segments, querySegment{isFilter: true, filter: f})
+			i = j
+			continue
+		}
+		start := i
+		for i < n && path[i] != '.' {
+			i++
+		}
+		segments = append(segments, querySegment{key: path[start:i]})
+	}
+	return segments, nil
+}
+
+// queryFilter is one or more "field OP value" comparisons joined by && / ||,
+// evaluated strictly left-to-right (no operator precedence or
+// parentheses) - a deliberately small subset, matching this CLI's other
+// hand-rolled expression evaluators (config's JSON Schema subset, the RCA
+// rule engine's condition DSL) rather than a full boolean-expression parser.
+type queryFilter struct {
+	clauses []filterClause
+	joiners []string // len(joiners) == len(clauses)-1, each "&&" or "||"
+}
+
+type filterClause struct {
+	field string
+	op    string // "==" or "!="
+	value string
+}
+
+func parseQueryFilter(expr string) (*queryFilter, error) {
+	expr = strings.TrimSpace(expr)
+	f := &queryFilter{}
+
+	rest := expr
+	for {
+		joiner := ""
+		idx := -1
+		if i := strings.Index(rest, "&&"); i >= 0 {
+			idx, joiner = i, "&&"
+		}
+		if i := strings.Index(rest, "||"); i >= 0 && (idx == -1 || i < idx) {
+			idx, joiner = i, "||"
+		}
+
+		var clauseText string
+		if idx == -1 {
+			clauseText = rest
+		} else {
+			clauseText = rest[:idx]
+		}
+		clause, err := parseFilterClause(clauseText)
+		if err != nil {
+			return nil, err
+		}
+		f.clauses = append(f.clauses, clause)
+		if idx == -1 {
+			break
+		}
+		f.joiners = append(f.joiners, joiner)
+		rest = rest[idx+2:]
+	}
+	return f, nil
+}
+
+func parseFilterClause(s string) (filterClause, error) {
+	s = strings.TrimSpace(s)
+	op := "=="
+	idx := strings.Index(s, "==")
+	if idx == -1 {
+		op = "!="
+		idx = strings.Index(s, "!=")
+	}
+	if idx == -1 {
+		return filterClause{}, fmt.Errorf("filter clause %q: expected '==' or '!='", s)
+	}
+	field := strings.TrimSpace(s[:idx])
+	value := strings.TrimSpace(s[idx+2:])
+	if unquoted, err := strconv.Unquote(value); err == nil {
+		value = unquoted
+	}
+	return filterClause{field: field, op: op, value: value}, nil
+}
+
+func (f *queryFilter) matches(entry map[string]string, prefix string) bool {
+	result := f.clauseMatches(f.clauses[0], entry, prefix)
+	for i, joiner := range f.joiners {
+		next := f.clauseMatches(f.clauses[i+1], entry, prefix)
+		if joiner == "&&" {
+			result = result && next
+		} else {
+			result = result || next
+		}
+	}
+	return result
+}
+
+func (f *queryFilter) clauseMatches(c filterClause, entry map[string]string, prefix string) bool {
+	key := c.field
+	if prefix != "" {
+		key = prefix + "." + c.field
+	}
+	actual, ok := entry[key]
+	if c.op == "!=" {
+		return !ok || actual != c.value
+	}
+	return ok && actual == c.value
+}
+
+// evalQueryPath walks segments against entry (a single line's flattened
+// field map, root-prefixed), returning the final field key(s) it resolves
+// to. A plain key extends every live prefix; a filter clause either tests
+// the whole entry (when no array prefix is active yet) or scans
+// "<prefix>[N]." keys for an index whose fields satisfy it.
+func evalQueryPath(segments []querySegment, entry map[string]string) []string {
+	prefixes := []string{""}
+
+	for _, seg := range segments {
+		var next []string
+		if seg.isFilter {
+			for _, prefix := range prefixes {
+				if prefix == "" {
+					if seg.filter.matches(entry, "") {
+						next = append(next, "")
+					}
+					continue
+				}
+				for _, idx := range arrayIndices(entry, prefix) {
+					candidate := fmt.Sprintf("%s[%d]", prefix, idx)
+					if seg.filter.matches(entry, candidate) {
+						next = append(next, candidate)
+					}
+				}
+			}
+		} else {
+			for _, prefix := range prefixes {
+				if prefix == "" {
+					next = append(next, seg.key)
+				} else {
+					next = append(next, prefix+"."+seg.key)
+				}
+			}
+		}
+		prefixes = next
+	}
+	return prefixes
+}
+
+// arrayIndices returns every distinct N such that entry has at least one
+// key of the form "<prefix>[N]..." or exactly "<prefix>[N]".
+func arrayIndices(entry map[string]string, prefix string) []int {
+	marker := prefix + "["
+	seen := map[int]bool{}
+	var out []int
+	for key := range entry {
+		if !strings.HasPrefix(key, marker) {
+			continue
+		}
+		rest := key[len(marker):]
+		end := strings.IndexByte(rest, ']')
+		if end == -1 {
+			continue
+		}
+		n, err := strconv.Atoi(rest[:end])
+		if err != nil || seen[n] {
+			continue
+		}
+		seen[n] = true
+		out = append(out, n)
+	}
+	sort.Ints(out)
+	return out
+}
@@ -0,0 +1,59 @@
+package troubleshoot
+
+import "testing"
+
+func TestQueryTopLevelFilter(t *testing.T) {
+	t.Parallel()
+
+	lines := []string{
+		`{"event":"tool_call","level":"info","call_id":"a1"}`,
+		`{"event":"tool_call","level":"error","call_id":"a2"}`,
+		`{"event":"other","level":"error","call_id":"a3"}`,
+	}
+
+	results, err := Query(lines, `#(event=="tool_call" && level=="error").call_id`)
+	if err != nil {
+		t.Fatalf("Query error: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d: %+v", len(results), results)
+	}
+	if results[0].Value != "a2" {
+		t.Fatalf("value=%q", results[0].Value)
+	}
+	if results[0].Line != 2 {
+		t.Fatalf("line=%d", results[0].Line)
+	}
+}
+
+func TestQueryArrayFilter(t *testing.T) {
+	t.Parallel()
+
+	lines := []string{
+		`{"event":"metrics_report","metrics":[{"name":"latency_ms","value":"42"},{"name":"jitter_ms","value":"3"}]}`,
+	}
+
+	value, ok, err := QueryScalar(lines, `metrics.#(name=="latency_ms").value`)
+	if err != nil {
+		t.Fatalf("QueryScalar error: %v", err)
+	}
+	if !ok {
+		t.Fatalf("expected a match")
+	}
+	if value != "42" {
+		t.Fatalf("value=%q", value)
+	}
+}
+
+func TestQueryNoMatchReturnsEmpty(t *testing.T) {
+	t.Parallel()
+
+	lines := []string{`{"event":"tool_call","level":"info"}`}
+	results, err := Query(lines, `#(event=="nope").call_id`)
+	if err != nil {
+		t.Fatalf("Query error: %v", err)
+	}
+	if len(results) != 0 {
+		t.Fatalf("expected no results, got %+v", results)
+	}
+}
@@ -24,6 +24,17 @@ type RCAHeader struct {
 	TransportProfileSampleRate int    `json:"tp_sample_rate,omitempty"`
 	TransportProfileSource     string `json:"tp_source,omitempty"`
 
+	// Active wizard.PipelineProfile snapshot, so RCA can validate against the
+	// profile actually selected rather than a global default.
+	ProfileName     string `json:"tp_profile_name,omitempty"`
+	ProfileChannels int    `json:"tp_profile_channels,omitempty"`
+	ProfileBitDepth int    `json:"tp_profile_bit_depth,omitempty"`
+
+	// FormatProfileID identifies the declarative FormatProfile (see
+	// format_profile.go) ai_engine selected for this call, so a profiles.yaml
+	// change can be correlated with calls that used it.
+	FormatProfileID string `json:"tp_profile_id,omitempty"`
+
 	AudioSocketFormat  string `json:"audiosocket_format,omitempty"`
 	AudioSocketHost    string `json:"audiosocket_host,omitempty"`
 	AudioSocketPort    int    `json:"audiosocket_port,omitempty"`
@@ -77,6 +88,8 @@ func ExtractRCAHeader(logData string) *RCAHeader {
 		h.DownstreamMode = fields["downstream_mode"]
 		h.TransportProfileEncoding = fields["tp_encoding"]
 		h.TransportProfileSource = fields["tp_source"]
+		h.ProfileName = fields["tp_profile_name"]
+		h.FormatProfileID = fields["tp_profile_id"]
 		h.AudioSocketFormat = fields["audiosocket_format"]
 		h.AudioSocketHost = fields["audiosocket_host"]
 		h.ExternalMediaCodec = fields["external_media_codec"]
@@ -84,6 +97,8 @@ func ExtractRCAHeader(logData string) *RCAHeader {
 		h.ExternalMediaAdvertiseHost = fields["external_media_advertise_host"]
 
 		h.TransportProfileSampleRate = atoi(fields["tp_sample_rate"])
+		h.ProfileChannels = atoi(fields["tp_profile_channels"])
+		h.ProfileBitDepth = atoi(fields["tp_profile_bit_depth"])
 		h.StreamingSampleRate = atoi(fields["streaming_sample_rate"])
 		h.StreamingJitterBufferMs = atoi(fields["streaming_jitter_buffer_ms"])
 		h.StreamingMinStartMs = atoi(fields["streaming_min_start_ms"])
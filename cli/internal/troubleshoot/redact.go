@@ -0,0 +1,151 @@
+package troubleshoot
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+)
+
+// RedactMode selects what a Redactor replaces a matched value with.
+type RedactMode int
+
+const (
+	// RedactMask replaces a key-name match with a fixed placeholder and a
+	// value-pattern match with a short leading prefix plus a placeholder
+	// (enough to eyeball "looks like a phone number" without seeing it).
+	RedactMask RedactMode = iota
+	// RedactHash replaces a matched value with "sha256:<8-hex>" of the
+	// original, so two lines redacting the same raw value still show the
+	// same token and can be correlated without the value itself leaking.
+	RedactHash
+)
+
+// redactPrefixLen is how many leading characters RedactMask keeps on a
+// value-pattern match, e.g. "+155***" for a phone number.
+const redactPrefixLen = 4
+
+// RedactOptions configures a Redactor.
+type RedactOptions struct {
+	// KeyPatterns match a field's *name*; any field whose name matches has
+	// its whole value redacted, regardless of what the value looks like.
+	KeyPatterns []*regexp.Regexp
+	// ValuePatterns match a field's (or the event string's) *value*
+	// wherever it occurs, independent of the field name.
+	ValuePatterns []*regexp.Regexp
+	Mode          RedactMode
+}
+
+// DefaultRedactOptions returns the rule set tuned for this repo's
+// adapters: caller/extension identifiers and credential-shaped key names
+// (api_key, authorization, secret, token, password), plus value-pattern
+// rules for phone numbers, bearer/sk-style API keys (shared with
+// anonymize's apiKeyLikeRe), and email addresses.
+func DefaultRedactOptions() RedactOptions {
+	return RedactOptions{
+		KeyPatterns: []*regexp.Regexp{
+			regexp.MustCompile(`(?i)^(caller_id|callerid|from|to|extension|ani|did)$`),
+			regexp.MustCompile(`(?i)(api[_-]?key|authorization|secret|token|password)`),
+		},
+		ValuePatterns: []*regexp.Regexp{
+			phoneNumberRe,
+			apiKeyLikeRe,
+			emailAddressRe,
+		},
+		Mode: RedactMask,
+	}
+}
+
+var emailAddressRe = regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}\b`)
+
+// Redactor rewrites an event string and a flattened field map according to
+// its RedactOptions, for callers assembling a support bundle or log excerpt
+// that might leave the box. It runs after parseLogLine/ParseStructured
+// (which is why it operates on the already-flattened field map rather than
+// raw JSON), alongside the existing anonymize/redactConfigYAML helpers that
+// scrub free text and config respectively.
+type Redactor struct {
+	opts RedactOptions
+}
+
+// NewRedactor builds a Redactor from opts. A zero-value RedactOptions (no
+// key or value patterns) redacts nothing, so it's always safe to build one
+// even if the caller only wants to turn it on conditionally.
+func NewRedactor(opts RedactOptions) *Redactor {
+	return &Redactor{opts: opts}
+}
+
+// NewDefaultRedactor is NewRedactor(DefaultRedactOptions()).
+func NewDefaultRedactor() *Redactor {
+	return NewRedactor(DefaultRedactOptions())
+}
+
+// Redact returns a redacted copy of event and fields. A nil *Redactor (the
+// zero value for an unset field in ExtractOptions) passes both through
+// unchanged, so existing callers see no behavior change.
+func (r *Redactor) Redact(event string, fields map[string]string) (string, map[string]string) {
+	if r == nil {
+		return event, fields
+	}
+
+	redactedEvent := r.redactValue(event)
+	if fields == nil {
+		return redactedEvent, nil
+	}
+
+	out := make(map[string]string, len(fields))
+	for k, v := range fields {
+		if r.keyMatches(k) {
+			out[k] = r.fullMask(v)
+			continue
+		}
+		out[k] = r.redactValue(v)
+	}
+	return redactedEvent, out
+}
+
+func (r *Redactor) keyMatches(key string) bool {
+	for _, re := range r.opts.KeyPatterns {
+		if re.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactValue applies every value-pattern rule to v, replacing each match
+// in place (so "call from +15551234567 to +15557654321" redacts both
+// numbers) rather than redacting v as a whole.
+func (r *Redactor) redactValue(v string) string {
+	for _, re := range r.opts.ValuePatterns {
+		v = re.ReplaceAllStringFunc(v, r.replace)
+	}
+	return v
+}
+
+// fullMask is the terminal substitution for a key-rule match: the whole
+// field value is credential-shaped (api_key, caller_id, ...), so unlike a
+// value-pattern match found inside a larger string, no prefix is kept.
+func (r *Redactor) fullMask(v string) string {
+	if r.opts.Mode == RedactHash {
+		return hashToken(v)
+	}
+	return "***"
+}
+
+// replace is the terminal substitution for one value-pattern match span
+// found inside a larger string (e.g. a phone number inside a log message).
+func (r *Redactor) replace(match string) string {
+	if r.opts.Mode == RedactHash {
+		return hashToken(match)
+	}
+	prefix := match
+	if len(prefix) > redactPrefixLen {
+		prefix = prefix[:redactPrefixLen]
+	}
+	return prefix + "***"
+}
+
+func hashToken(v string) string {
+	sum := sha256.Sum256([]byte(v))
+	return "sha256:" + hex.EncodeToString(sum[:])[:8]
+}
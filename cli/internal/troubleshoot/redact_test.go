@@ -0,0 +1,67 @@
+package troubleshoot
+
+import "testing"
+
+func TestRedactorMasksKeyAndValuePatterns(t *testing.T) {
+	t.Parallel()
+
+	r := NewDefaultRedactor()
+	event, fields := r.Redact("call from +15551234567", map[string]string{
+		"caller_id": "+15551234567",
+		"api_key":   "sk-abcdefghijklmnopqrstuvwx",
+		"status":    "ok",
+	})
+
+	if event != "call from +155***" {
+		t.Fatalf("event=%q", event)
+	}
+	if fields["caller_id"] != "***" {
+		t.Fatalf("caller_id=%q, want fully masked (key-name rule)", fields["caller_id"])
+	}
+	if fields["api_key"] != "***" {
+		t.Fatalf("api_key=%q, want fully masked (key-name rule)", fields["api_key"])
+	}
+	if fields["status"] != "ok" {
+		t.Fatalf("status=%q, want untouched", fields["status"])
+	}
+}
+
+func TestRedactorKeepHashedCorrelates(t *testing.T) {
+	t.Parallel()
+
+	opts := DefaultRedactOptions()
+	opts.Mode = RedactHash
+	r := NewRedactor(opts)
+
+	_, a := r.Redact("", map[string]string{"caller_id": "+15551234567"})
+	_, b := r.Redact("", map[string]string{"caller_id": "+15551234567"})
+	if a["caller_id"] != b["caller_id"] {
+		t.Fatalf("expected identical hashed tokens for identical input, got %q vs %q", a["caller_id"], b["caller_id"])
+	}
+	if a["caller_id"] == "+15551234567" {
+		t.Fatalf("hashed token must not equal the raw value")
+	}
+}
+
+func TestNilRedactorPassesThrough(t *testing.T) {
+	t.Parallel()
+
+	var r *Redactor
+	event, fields := r.Redact("hello", map[string]string{"caller_id": "+15551234567"})
+	if event != "hello" || fields["caller_id"] != "+15551234567" {
+		t.Fatalf("nil Redactor should be a no-op, got event=%q fields=%v", event, fields)
+	}
+}
+
+func TestExtractToolCallsWithOptionsRedactsArguments(t *testing.T) {
+	t.Parallel()
+
+	logData := "[info     ] tool call: dial_out(+15551234567) [src] function_call_id=1\n"
+	calls := ExtractToolCallsWithOptions(logData, ExtractOptions{Redact: NewDefaultRedactor()})
+	if len(calls) != 1 {
+		t.Fatalf("expected 1 tool call, got %d", len(calls))
+	}
+	if calls[0].Arguments == "+15551234567" {
+		t.Fatalf("expected Arguments to be redacted, got %q", calls[0].Arguments)
+	}
+}
@@ -0,0 +1,179 @@
+// Package replay turns a --capture pcap into an HLS playlist of WAV
+// segments so operators can step through a call's audio in an HLS-capable
+// player instead of reading logs.
+//
+// LIMITATION: true HLS requires .ts (MPEG-TS) or fMP4 segments; this
+// module doesn't vendor a transport-stream/fMP4 muxer, so segments are
+// written as .wav (via capture.ExportWAV) and the manifest's segment URIs
+// point at those .wav files. Most HLS players expect .ts/.m4s and will
+// reject this as non-conformant — this is an honest placeholder for that
+// piece, not a full HLS implementation. The tags it writes
+// (#EXT-X-TARGETDURATION, #EXTINF, #EXT-X-ENDLIST, and the custom
+// #EXT-X-RCA-EVENT annotation) otherwise follow standard playlist syntax;
+// no #EXT-X-DISCONTINUITY tag is written.
+package replay
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/troubleshoot/capture"
+)
+
+// Event marks a moment in the call worth its own segment boundary (e.g. a
+// VAD transition or barge-in), rendered as a #EXT-X-RCA-EVENT annotation
+// on the #EXTINF tag for the segment it falls in.
+type Event struct {
+	AtMs int
+	Kind string // e.g. "TTS_START", "BARGE_IN", "PROVIDER_RECONNECT"
+}
+
+// Options configures manifest generation.
+type Options struct {
+	CallID    string
+	OutputDir string
+	RTPPort   int // 0 matches any UDP port in the pcap
+	// SegmentMs is the target segment duration when no Event falls inside
+	// it yet (HLS's #EXT-X-TARGETDURATION). Defaults to 4000ms.
+	SegmentMs int
+}
+
+func (o Options) withDefaults() Options {
+	if o.SegmentMs <= 0 {
+		o.SegmentMs = 4000
+	}
+	return o
+}
+
+// Manifest describes the generated playlist.
+type Manifest struct {
+	Path string // filesystem path to the .m3u8
+	URL  string // file:// URL FormatAlignment.FormatForLLM can print
+}
+
+// GenerateManifest decodes pcapPath's captured audio and writes a VOD-style
+// HLS playlist plus one .wav segment per span into opts.OutputDir. Segment
+// boundaries are those of segmentBoundaries (0, every opts.SegmentMs tick,
+// every Event.AtMs inside the call, and the end of the call), so every
+// event before the end of the call lands on a segment start, and that
+// segment gets one #EXT-X-RCA-EVENT tag (the first event at that offset).
+//
+// This only produces a complete (#EXT-X-ENDLIST) playlist from an already
+// finished pcap. Tailing an in-progress call would mean calling this
+// again as the capture grows and re-writing the same path, appending new
+// segments instead of rebuilding from scratch — not implemented here.
+func GenerateManifest(pcapPath string, opts Options, events []Event) (*Manifest, error) {
+	opts = opts.withDefaults()
+	if opts.CallID == "" {
+		return nil, fmt.Errorf("replay: CallID is required")
+	}
+	if opts.OutputDir == "" {
+		return nil, fmt.Errorf("replay: OutputDir is required")
+	}
+	if err := os.MkdirAll(opts.OutputDir, 0o755); err != nil {
+		return nil, fmt.Errorf("replay: failed to create output dir: %w", err)
+	}
+
+	samples, err := capture.ExtractAudioSegment(pcapPath, opts.RTPPort, 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("replay: failed to decode %s: %w", pcapPath, err)
+	}
+	if len(samples) == 0 {
+		return nil, fmt.Errorf("replay: no audio decoded from %s", pcapPath)
+	}
+
+	const sampleRateHz = 8000 // PCMU/AudioSocket slin telephony rate (see capture/metrics.go)
+	totalMs := len(samples) * 1000 / sampleRateHz
+
+	boundaries := segmentBoundaries(events, totalMs, opts.SegmentMs)
+
+	var b strings.Builder
+	b.WriteString("#EXTM3U\n")
+	b.WriteString("#EXT-X-VERSION:3\n")
+	b.WriteString(fmt.Sprintf("#EXT-X-TARGETDURATION:%d\n", (opts.SegmentMs+999)/1000))
+	b.WriteString("#EXT-X-MEDIA-SEQUENCE:0\n")
+	b.WriteString("#EXT-X-PLAYLIST-TYPE:VOD\n")
+
+	for i := 0; i < len(boundaries)-1; i++ {
+		startMs, endMs := boundaries[i], boundaries[i+1]
+		startSample := startMs * sampleRateHz / 1000
+		endSample := endMs * sampleRateHz / 1000
+		if endSample > len(samples) {
+			endSample = len(samples)
+		}
+		if startSample >= endSample {
+			continue
+		}
+		segSamples := samples[startSample:endSample]
+		segName := fmt.Sprintf("%s_%04d.wav", sanitizeCallID(opts.CallID), i)
+		segPath := filepath.Join(opts.OutputDir, segName)
+		if err := os.WriteFile(segPath, capture.ExportWAV(segSamples, sampleRateHz), 0o644); err != nil {
+			return nil, fmt.Errorf("replay: failed to write segment %s: %w", segPath, err)
+		}
+
+		durationSec := float64(endMs-startMs) / 1000.0
+		if kind := eventKindAt(events, startMs); kind != "" {
+			b.WriteString(fmt.Sprintf("#EXT-X-RCA-EVENT:%s\n", kind))
+		}
+		b.WriteString(fmt.Sprintf("#EXTINF:%.3f,\n", durationSec))
+		b.WriteString(segName + "\n")
+	}
+	b.WriteString("#EXT-X-ENDLIST\n")
+
+	manifestName := sanitizeCallID(opts.CallID) + ".m3u8"
+	manifestPath := filepath.Join(opts.OutputDir, manifestName)
+	if err := os.WriteFile(manifestPath, []byte(b.String()), 0o644); err != nil {
+		return nil, fmt.Errorf("replay: failed to write manifest: %w", err)
+	}
+
+	return &Manifest{Path: manifestPath, URL: "file://" + manifestPath}, nil
+}
+
+// segmentBoundaries returns the sorted, deduplicated set of millisecond
+// offsets at which a new segment must start: 0, every Event.AtMs, every
+// segmentMs tick, and totalMs.
+func segmentBoundaries(events []Event, totalMs, segmentMs int) []int {
+	set := map[int]bool{0: true, totalMs: true}
+	for ms := segmentMs; ms < totalMs; ms += segmentMs {
+		set[ms] = true
+	}
+	for _, e := range events {
+		if e.AtMs >= 0 && e.AtMs <= totalMs {
+			set[e.AtMs] = true
+		}
+	}
+	boundaries := make([]int, 0, len(set))
+	for ms := range set {
+		boundaries = append(boundaries, ms)
+	}
+	sort.Ints(boundaries)
+	return boundaries
+}
+
+// eventKindAt returns the Kind of the first event at exactly atMs, or "".
+func eventKindAt(events []Event, atMs int) string {
+	for _, e := range events {
+		if e.AtMs == atMs {
+			return e.Kind
+		}
+	}
+	return ""
+}
+
+// sanitizeCallID mirrors capture's own filename sanitization so replay
+// segment/manifest names stay consistent with .pcap naming.
+func sanitizeCallID(callID string) string {
+	var b strings.Builder
+	for _, r := range callID {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,90 @@
+package troubleshoot
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// junitChecks maps the RCA quality issues produced by evaluateCallQuality
+// (see computeCallQuality) onto the fixed set of named JUnit checks CI
+// tooling can key off of. transport_detected isn't one of
+// evaluateCallQuality's issues and is handled separately in RenderJUnit.
+var junitChecks = []struct {
+	name  string
+	match func(issue string) bool
+}{
+	{"provider_ratio", func(issue string) bool { return strings.Contains(issue, "pacing") }},
+	{"drift", func(issue string) bool { return strings.Contains(strings.ToLower(issue), "drift") }},
+	{"underflows", func(issue string) bool { return strings.Contains(strings.ToLower(issue), "underflow") }},
+	{"gate_flutter", func(issue string) bool { return strings.Contains(strings.ToLower(issue), "gate flutter") }},
+	{"format_alignment", func(issue string) bool { return strings.Contains(strings.ToLower(issue), "mismatch") }},
+}
+
+type junitTestsuite struct {
+	XMLName   xml.Name         `xml:"testsuite"`
+	Name      string           `xml:"name,attr"`
+	Tests     int              `xml:"tests,attr"`
+	Failures  int              `xml:"failures,attr"`
+	Skipped   int              `xml:"skipped,attr"`
+	TestCases []junitTestcase  `xml:"testcase"`
+}
+
+type junitTestcase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *struct{}     `xml:"skipped,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// RenderJUnit renders rep as a JUnit XML testsuite, one <testcase> per named
+// RCA quality check (transport_detected, drift, underflows, gate_flutter,
+// format_alignment, provider_ratio), so CI can gate merges on the same
+// checks `agent rca` prints for humans. Failure messages are exactly the
+// issue strings evaluateCallQuality produced (see computeCallQuality),
+// except for transport_detected, which evaluateCallQuality doesn't cover.
+// Checks are marked <skipped/> when rep.Metrics has no evidence to score.
+func RenderJUnit(rep *RCAReport) (string, error) {
+	suite := junitTestsuite{
+		Name:  fmt.Sprintf("agent.rca.%s", rep.CallID),
+		Tests: len(junitChecks) + 1,
+	}
+
+	transport := junitTestcase{Name: "transport_detected", ClassName: suite.Name}
+	if rep.AudioTransport == "" || rep.AudioTransport == "unknown" {
+		msg := "no AudioSocket/ExternalMedia transport detected in logs"
+		transport.Failure = &junitFailure{Message: msg, Text: msg}
+		suite.Failures++
+	}
+	suite.TestCases = append(suite.TestCases, transport)
+
+	if rep.Metrics == nil {
+		for _, c := range junitChecks {
+			suite.TestCases = append(suite.TestCases, junitTestcase{Name: c.name, ClassName: suite.Name, Skipped: &struct{}{}})
+			suite.Skipped++
+		}
+	} else {
+		for _, c := range junitChecks {
+			tc := junitTestcase{Name: c.name, ClassName: suite.Name}
+			for _, issue := range rep.QualityIssues {
+				if c.match(issue) {
+					tc.Failure = &junitFailure{Message: issue, Text: issue}
+					suite.Failures++
+					break
+				}
+			}
+			suite.TestCases = append(suite.TestCases, tc)
+		}
+	}
+
+	out, err := xml.MarshalIndent(suite, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return xml.Header + string(out), nil
+}
@@ -0,0 +1,61 @@
+package troubleshoot
+
+import (
+	"strings"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/troubleshoot/rules"
+)
+
+// buildRuleContext computes the named counters/flags rules.yaml "when"
+// expressions and templates can reference for this call. Kept in one place
+// so a new rule and a new hardcoded analyze* check can cite the same count.
+func buildRuleContext(analysis *Analysis, logData string) rules.Context {
+	lower := strings.ToLower(logData)
+	callEvents := ParseLogEvents(logData).ForCallID(analysis.CallID)
+
+	underflowCount := callEvents.CountEventContains("underflow")
+	if underflowCount == 0 {
+		underflowCount = strings.Count(lower, "underflow")
+	}
+	gateEventCount := callEvents.CountEventContains("gate") + callEvents.CountEventContains("gating")
+
+	return rules.Context{
+		"transport":           strings.ToLower(strings.TrimSpace(analysis.AudioTransport)),
+		"underflow_count":     underflowCount,
+		"gate_event_count":    gateEventCount,
+		"echo_evidence_count": echoEvidenceCount(lower),
+		"has_transcription":   strings.Contains(lower, "transcription") || strings.Contains(lower, "transcript"),
+		"has_playback":        strings.Contains(lower, "playback") || strings.Contains(lower, "playing"),
+	}
+}
+
+// ApplyRules runs rs against analysis/logData and appends any matching
+// rule's rendered Finding/RootCause/Actions to analysis.SymptomAnalysis for
+// the current symptom. It is additive: SymptomAnalysis must already have
+// been initialized by SymptomChecker.AnalyzeSymptom, and a rule engine
+// error for one rule doesn't block the others (best-effort, same as the
+// rest of symptom analysis).
+func ApplyRules(analysis *Analysis, logData string, rs rules.Ruleset) {
+	if analysis.SymptomAnalysis == nil {
+		return
+	}
+	ctx := buildRuleContext(analysis, logData)
+	for _, rule := range rs {
+		if rule.Symptom != analysis.SymptomAnalysis.Symptom {
+			continue
+		}
+		matched, err := rule.Eval(ctx)
+		if err != nil || !matched {
+			continue
+		}
+		finding, err := rule.RenderFinding(ctx)
+		if err != nil {
+			continue
+		}
+		analysis.SymptomAnalysis.Findings = append(analysis.SymptomAnalysis.Findings, finding)
+		if rule.RootCause != "" {
+			analysis.SymptomAnalysis.RootCauses = append(analysis.SymptomAnalysis.RootCauses, rule.RootCause)
+		}
+		analysis.SymptomAnalysis.Actions = append(analysis.SymptomAnalysis.Actions, rule.Actions...)
+	}
+}
@@ -0,0 +1,494 @@
+// Package rules implements a small, data-driven rule engine for
+// troubleshoot symptom analysis: a Rule declares a boolean "when" expression
+// over named counters/flags, and renders Finding/RootCause/Actions templates
+// when it matches. The goal is letting support teams extend symptom
+// coverage by dropping a YAML file into /etc/asterisk-ai/rules.d/ instead of
+// recompiling the CLI.
+//
+// The shipped default.yaml (embedded below) is an initial ruleset covering
+// the conditions the hardcoded SymptomChecker analyze* methods already
+// check; it is not a full 1:1 port of every Go condition, and those Go
+// methods remain the ground truth and keep running unconditionally. This
+// package's output is additive.
+package rules
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed default/*.yaml
+var defaultFS embed.FS
+
+// SiteRulesDir is where operators can drop site-specific rule files,
+// merged on top of the default ruleset (see Merge).
+const SiteRulesDir = "/etc/asterisk-ai/rules.d"
+
+// Rule is one data-driven symptom check.
+type Rule struct {
+	ID        string   `yaml:"id"`
+	Symptom   string   `yaml:"symptom"`
+	When      string   `yaml:"when"`
+	Severity  string   `yaml:"severity"`
+	Finding   string   `yaml:"finding"`
+	RootCause string   `yaml:"root_cause"`
+	Actions   []string `yaml:"actions"`
+}
+
+// file is the on-disk/embedded shape: a top-level "rules:" list.
+type file struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+// Ruleset is an ordered collection of rules, keyed by ID for overrides.
+type Ruleset []Rule
+
+// LoadDefault returns the ruleset embedded in the binary.
+func LoadDefault() (Ruleset, error) {
+	data, err := defaultFS.ReadFile("default/default.yaml")
+	if err != nil {
+		return nil, fmt.Errorf("rules: failed to read embedded default ruleset: %w", err)
+	}
+	return parse(data)
+}
+
+// LoadDir reads every *.yaml/*.yml file in dir (non-recursive) and
+// concatenates their rules. A missing dir is not an error — it simply
+// contributes no rules, since SiteRulesDir won't exist on most hosts.
+func LoadDir(dir string) (Ruleset, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("rules: failed to read %s: %w", dir, err)
+	}
+
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		ext := strings.ToLower(filepath.Ext(e.Name()))
+		if ext == ".yaml" || ext == ".yml" {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+
+	var rs Ruleset
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("rules: failed to read %s: %w", name, err)
+		}
+		parsed, err := parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("rules: %s: %w", name, err)
+		}
+		rs = append(rs, parsed...)
+	}
+	return rs, nil
+}
+
+func parse(data []byte) (Ruleset, error) {
+	var f file
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("invalid rule YAML: %w", err)
+	}
+	return Ruleset(f.Rules), nil
+}
+
+// Merge layers overrides on top of base: an override rule with the same ID
+// as a base rule replaces it in place; a new ID is appended.
+func Merge(base, overrides Ruleset) Ruleset {
+	out := make(Ruleset, len(base))
+	copy(out, base)
+	indexByID := make(map[string]int, len(out))
+	for i, r := range out {
+		indexByID[r.ID] = i
+	}
+	for _, r := range overrides {
+		if i, ok := indexByID[r.ID]; ok {
+			out[i] = r
+		} else {
+			indexByID[r.ID] = len(out)
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// LoadMerged loads the embedded default ruleset and layers SiteRulesDir (or
+// the TROUBLESHOOT_RULES_DIR override, if set) on top of it.
+func LoadMerged() (Ruleset, error) {
+	base, err := LoadDefault()
+	if err != nil {
+		return nil, err
+	}
+	dir := os.Getenv("TROUBLESHOOT_RULES_DIR")
+	if dir == "" {
+		dir = SiteRulesDir
+	}
+	site, err := LoadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	return Merge(base, site), nil
+}
+
+// Context is the named counters/flags a When expression and template can
+// reference. Keys are looked up case-sensitively; missing keys evaluate to
+// their zero value rather than erroring, so a rule referencing a counter an
+// older build doesn't populate yet degrades to "doesn't match" instead of
+// failing the whole pass.
+type Context map[string]interface{}
+
+// Validate checks every rule for required fields and a parseable When
+// expression/templates, without needing a Context. It's what `rules
+// validate` runs.
+func Validate(rs Ruleset) []error {
+	var errs []error
+	seen := make(map[string]bool, len(rs))
+	for _, r := range rs {
+		if r.ID == "" {
+			errs = append(errs, fmt.Errorf("rule missing id"))
+			continue
+		}
+		if seen[r.ID] {
+			errs = append(errs, fmt.Errorf("rule %q: duplicate id", r.ID))
+		}
+		seen[r.ID] = true
+		if r.Symptom == "" {
+			errs = append(errs, fmt.Errorf("rule %q: missing symptom", r.ID))
+		}
+		if r.When == "" {
+			errs = append(errs, fmt.Errorf("rule %q: missing when expression", r.ID))
+		} else if _, err := parseExpr(r.When); err != nil {
+			errs = append(errs, fmt.Errorf("rule %q: invalid when expression: %w", r.ID, err))
+		}
+		if r.Finding == "" {
+			errs = append(errs, fmt.Errorf("rule %q: missing finding", r.ID))
+		} else if _, err := render(r.Finding, Context{}); err != nil {
+			errs = append(errs, fmt.Errorf("rule %q: invalid finding template: %w", r.ID, err))
+		}
+	}
+	return errs
+}
+
+// Eval reports whether rule's When expression matches ctx.
+func (r Rule) Eval(ctx Context) (bool, error) {
+	expr, err := parseExpr(r.When)
+	if err != nil {
+		return false, err
+	}
+	return expr.eval(ctx), nil
+}
+
+// RenderFinding renders the Finding template against ctx (template vars are
+// referenced as {{.count}}-style dotted names from ctx's keys).
+func (r Rule) RenderFinding(ctx Context) (string, error) {
+	return render(r.Finding, ctx)
+}
+
+func render(tmplStr string, ctx Context) (string, error) {
+	t, err := template.New("rule").Option("missingkey=zero").Parse(tmplStr)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, map[string]interface{}(ctx)); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// ---- a small boolean-expression evaluator over Context ----
+//
+// Grammar (left-to-right, no operator precedence beyond && binding tighter
+// than ||, parens for grouping):
+//
+//	expr       := orTerm
+//	orTerm     := andTerm ("||" andTerm)*
+//	andTerm    := atom ("&&" atom)*
+//	atom       := "(" expr ")" | comparison
+//	comparison := IDENT OP VALUE
+//	OP         := "==" | "!=" | ">=" | "<=" | ">" | "<"
+//	VALUE      := true | false | number | 'quoted string'
+
+type exprNode interface {
+	eval(ctx Context) bool
+}
+
+type orNode struct{ terms []exprNode }
+
+func (n orNode) eval(ctx Context) bool {
+	for _, t := range n.terms {
+		if t.eval(ctx) {
+			return true
+		}
+	}
+	return false
+}
+
+type andNode struct{ terms []exprNode }
+
+func (n andNode) eval(ctx Context) bool {
+	for _, t := range n.terms {
+		if !t.eval(ctx) {
+			return false
+		}
+	}
+	return true
+}
+
+type cmpNode struct {
+	ident string
+	op    string
+	value interface{}
+}
+
+func (n cmpNode) eval(ctx Context) bool {
+	actual, ok := ctx[n.ident]
+	if !ok {
+		actual = zeroValueLike(n.value)
+	}
+	switch a := actual.(type) {
+	case bool:
+		b, _ := n.value.(bool)
+		switch n.op {
+		case "==":
+			return a == b
+		case "!=":
+			return a != b
+		}
+		return false
+	case string:
+		b, _ := n.value.(string)
+		switch n.op {
+		case "==":
+			return a == b
+		case "!=":
+			return a != b
+		}
+		return false
+	case int:
+		b, _ := n.value.(int)
+		switch n.op {
+		case "==":
+			return a == b
+		case "!=":
+			return a != b
+		case ">":
+			return a > b
+		case ">=":
+			return a >= b
+		case "<":
+			return a < b
+		case "<=":
+			return a <= b
+		}
+	}
+	return false
+}
+
+func zeroValueLike(v interface{}) interface{} {
+	switch v.(type) {
+	case bool:
+		return false
+	case string:
+		return ""
+	default:
+		return 0
+	}
+}
+
+type parser struct {
+	tokens []string
+	pos    int
+}
+
+func parseExpr(expr string) (exprNode, error) {
+	p := &parser{tokens: tokenizeExpr(expr)}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.tokens) {
+		return nil, fmt.Errorf("unexpected token %q", p.tokens[p.pos])
+	}
+	return node, nil
+}
+
+func (p *parser) peek() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() string {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *parser) parseOr() (exprNode, error) {
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	terms := []exprNode{first}
+	for p.peek() == "||" {
+		p.next()
+		t, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, t)
+	}
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return orNode{terms: terms}, nil
+}
+
+func (p *parser) parseAnd() (exprNode, error) {
+	first, err := p.parseAtom()
+	if err != nil {
+		return nil, err
+	}
+	terms := []exprNode{first}
+	for p.peek() == "&&" {
+		p.next()
+		t, err := p.parseAtom()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, t)
+	}
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return andNode{terms: terms}, nil
+}
+
+func (p *parser) parseAtom() (exprNode, error) {
+	if p.peek() == "(" {
+		p.next()
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("expected ')'")
+		}
+		p.next()
+		return node, nil
+	}
+	return p.parseComparison()
+}
+
+var compareOps = []string{"==", "!=", ">=", "<=", ">", "<"}
+
+func (p *parser) parseComparison() (exprNode, error) {
+	ident := p.next()
+	if ident == "" {
+		return nil, fmt.Errorf("expected identifier")
+	}
+	op := p.next()
+	isOp := false
+	for _, o := range compareOps {
+		if op == o {
+			isOp = true
+			break
+		}
+	}
+	if !isOp {
+		return nil, fmt.Errorf("expected comparison operator after %q, got %q", ident, op)
+	}
+	valTok := p.next()
+	value, err := parseValue(valTok)
+	if err != nil {
+		return nil, err
+	}
+	return cmpNode{ident: ident, op: op, value: value}, nil
+}
+
+func parseValue(tok string) (interface{}, error) {
+	if tok == "true" {
+		return true, nil
+	}
+	if tok == "false" {
+		return false, nil
+	}
+	if len(tok) >= 2 && (tok[0] == '\'' || tok[0] == '"') && tok[len(tok)-1] == tok[0] {
+		return tok[1 : len(tok)-1], nil
+	}
+	if n, err := strconv.Atoi(tok); err == nil {
+		return n, nil
+	}
+	return nil, fmt.Errorf("invalid value %q", tok)
+}
+
+// tokenizeExpr splits a When expression into identifiers, operators,
+// parens, and quoted/numeric/bool literals.
+func tokenizeExpr(expr string) []string {
+	var tokens []string
+	i := 0
+	for i < len(expr) {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '(' || c == ')':
+			tokens = append(tokens, string(c))
+			i++
+		case strings.HasPrefix(expr[i:], "&&"):
+			tokens = append(tokens, "&&")
+			i += 2
+		case strings.HasPrefix(expr[i:], "||"):
+			tokens = append(tokens, "||")
+			i += 2
+		case strings.HasPrefix(expr[i:], "=="), strings.HasPrefix(expr[i:], "!="),
+			strings.HasPrefix(expr[i:], ">="), strings.HasPrefix(expr[i:], "<="):
+			tokens = append(tokens, expr[i:i+2])
+			i += 2
+		case c == '>' || c == '<':
+			tokens = append(tokens, string(c))
+			i++
+		case c == '\'' || c == '"':
+			quote := c
+			j := i + 1
+			for j < len(expr) && expr[j] != quote {
+				j++
+			}
+			tokens = append(tokens, expr[i:min(j+1, len(expr))])
+			i = j + 1
+		default:
+			j := i
+			for j < len(expr) && expr[j] != ' ' && expr[j] != '\t' && expr[j] != '(' && expr[j] != ')' {
+				j++
+			}
+			tokens = append(tokens, expr[i:j])
+			i = j
+		}
+	}
+	return tokens
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
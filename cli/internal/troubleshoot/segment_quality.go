@@ -0,0 +1,131 @@
+package troubleshoot
+
+import (
+	"fmt"
+	"sort"
+)
+
+// SegmentQuality is a per-segment quality sub-score computed by
+// scoreSegments. evaluateCallQuality aggregates these (duration-weighted)
+// into the overall call score, and displayCallQuality surfaces the worst
+// few so a handful of bad segments don't get averaged away.
+type SegmentQuality struct {
+	Index              int
+	StreamID           string
+	StartOffsetSeconds float64
+	DurationSeconds    float64
+	DriftPct           float64
+	Score              float64
+	DominantIssue      string
+}
+
+// scoreSegments walks metrics.StreamingSummaries (skipping greeting
+// segments) and scores each one from its own DriftPct, plus the call-level
+// underflow-rate, gate-flutter, and provider-ratio signals applied
+// uniformly — ai_engine's logs don't attribute underflows/gate closures/
+// provider bytes to individual segments today, only to the call as a
+// whole, so those three terms can't actually vary segment-to-segment yet.
+//
+// StartOffsetSeconds is a running total of prior segments' WallSeconds in
+// log-emission order. There's no per-segment wall-clock timestamp in the
+// logs, so this is an emission-order approximation, not a true offset.
+func scoreSegments(metrics *CallMetrics) []SegmentQuality {
+	if metrics == nil || len(metrics.StreamingSummaries) == 0 {
+		return nil
+	}
+
+	totalFrames := 0
+	for _, seg := range metrics.StreamingSummaries {
+		totalFrames += seg.BytesSent / 320
+	}
+	var underflowRate float64
+	if metrics.UnderflowCount > 0 && totalFrames > 0 {
+		underflowRate = float64(metrics.UnderflowCount) / float64(totalFrames) * 100
+	}
+
+	providerRatioOff := false
+	if len(metrics.ProviderSegments) > 0 && metrics.ProviderBytesTotal > 0 {
+		ratio := float64(metrics.EnqueuedBytesTotal) / float64(metrics.ProviderBytesTotal)
+		providerRatioOff = ratio < 0.95 || ratio > 1.05
+	}
+
+	var out []SegmentQuality
+	offset := 0.0
+	for i, seg := range metrics.StreamingSummaries {
+		dur := seg.WallSeconds
+		if dur <= 0 {
+			dur = seg.EffectiveSeconds
+		}
+		if seg.IsGreeting {
+			offset += dur
+			continue
+		}
+
+		score := 100.0
+		dominant := ""
+
+		switch d := seg.DriftPct; {
+		case d > 10.0 || d < -10.0:
+			score -= 25.0
+			dominant = fmt.Sprintf("high drift (%.1f%%)", d)
+		case d > 5.0 || d < -5.0:
+			score -= 10.0
+			dominant = fmt.Sprintf("elevated drift (%.1f%%)", d)
+		}
+
+		switch {
+		case underflowRate >= 5.0:
+			score -= 20.0
+			if dominant == "" {
+				dominant = fmt.Sprintf("underflows (%.1f%% rate)", underflowRate)
+			}
+		case underflowRate >= 1.0:
+			score -= 5.0
+			if dominant == "" {
+				dominant = fmt.Sprintf("underflows (%.1f%% rate)", underflowRate)
+			}
+		}
+
+		if metrics.GateFlutterDetected {
+			score -= 10.0
+			if dominant == "" {
+				dominant = "gate flutter"
+			}
+		}
+
+		if providerRatioOff {
+			score -= 10.0
+			if dominant == "" {
+				dominant = "provider pacing"
+			}
+		}
+
+		if score < 0 {
+			score = 0
+		}
+
+		out = append(out, SegmentQuality{
+			Index:              i,
+			StreamID:           seg.StreamID,
+			StartOffsetSeconds: offset,
+			DurationSeconds:    dur,
+			DriftPct:           seg.DriftPct,
+			Score:              score,
+			DominantIssue:      dominant,
+		})
+		offset += dur
+	}
+	return out
+}
+
+// worstSegments returns up to n segments from segs with the lowest score,
+// worst first.
+func worstSegments(segs []SegmentQuality, n int) []SegmentQuality {
+	sorted := make([]SegmentQuality, len(segs))
+	copy(sorted, segs)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Score < sorted[j].Score })
+	if len(sorted) > n {
+		sorted = sorted[:n]
+	}
+	return sorted
+}
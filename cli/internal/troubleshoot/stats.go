@@ -0,0 +1,311 @@
+package troubleshoot
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// StatsOptions configures RunStats (`agent rca stats`): the time window and
+// calls to aggregate over.
+type StatsOptions struct {
+	Since string // e.g. "24h"; parsed with time.ParseDuration (default: 24h)
+	Limit int    // max calls to pull from getRecentCallsWindow (default: 200)
+	Grep  string // only include calls whose collected log slice matches this (regex, falls back to substring)
+}
+
+func (o StatsOptions) withDefaults() StatsOptions {
+	if o.Since == "" {
+		o.Since = "24h"
+	}
+	if o.Limit <= 0 {
+		o.Limit = 200
+	}
+	return o
+}
+
+// Percentiles holds p50/p90/p99 for one numeric CallMetrics field across the
+// aggregated calls.
+type Percentiles struct {
+	P50 float64 `json:"p50"`
+	P90 float64 `json:"p90"`
+	P99 float64 `json:"p99"`
+}
+
+// FailureReason is one entry in RCAAggregate's top-failure-reasons table: a
+// normalized error signature (timestamps/ids stripped) and how many calls hit it.
+type FailureReason struct {
+	Signature string `json:"signature"`
+	Count     int    `json:"count"`
+}
+
+// RCAAggregate summarizes RCA results across many calls instead of one.
+type RCAAggregate struct {
+	Since     string `json:"since"`
+	CallCount int    `json:"call_count"`
+
+	ByProvider      map[string]int `json:"by_provider,omitempty"`
+	ByTransport     map[string]int `json:"by_transport,omitempty"`
+	ByErrorCategory map[string]int `json:"by_error_category,omitempty"`
+
+	// Percentile histograms. Only computed for fields the log pipeline
+	// actually emits today (drift/underflow/gate-closure counts); per-call
+	// barge-in/first-audio/TTS-start latency events don't exist yet, so
+	// those histograms are omitted rather than faked.
+	DriftPctPercentiles       *Percentiles `json:"drift_pct_percentiles,omitempty"`
+	UnderflowCountPercentiles *Percentiles `json:"underflow_count_percentiles,omitempty"`
+	GateClosuresPercentiles   *Percentiles `json:"gate_closures_percentiles,omitempty"`
+
+	TopFailureReasons []FailureReason `json:"top_failure_reasons,omitempty"`
+}
+
+// RunStats runs RCA across the last N calls (or a time window) and prints an
+// aggregate report instead of a single call's RCA.
+func (r *Runner) RunStats(opts StatsOptions) error {
+	LoadEnvFile()
+	opts = opts.withDefaults()
+
+	since, err := time.ParseDuration(opts.Since)
+	if err != nil {
+		return fmt.Errorf("invalid --since %q: %w", opts.Since, err)
+	}
+	sinceStr := formatDockerSince(since)
+
+	calls, err := r.getRecentCallsSince(opts.Limit, sinceStr)
+	if err != nil {
+		return fmt.Errorf("failed to get recent calls: %w", err)
+	}
+
+	var grepRe *regexp.Regexp
+	if opts.Grep != "" {
+		if re, err := regexp.Compile(opts.Grep); err == nil {
+			grepRe = re
+		}
+	}
+
+	agg := &RCAAggregate{
+		Since:           opts.Since,
+		ByProvider:      make(map[string]int),
+		ByTransport:     make(map[string]int),
+		ByErrorCategory: make(map[string]int),
+	}
+
+	var driftVals, underflowVals, gateVals []float64
+	signatureCounts := make(map[string]int)
+
+	for _, call := range calls {
+		sub := &Runner{verbose: r.verbose, ctx: r.ctx, callID: call.ID}
+		logData, err := sub.collectCallData()
+		if err != nil || strings.TrimSpace(logData) == "" {
+			continue
+		}
+		if opts.Grep != "" {
+			if grepRe != nil {
+				if !grepRe.MatchString(logData) {
+					continue
+				}
+			} else if !strings.Contains(logData, opts.Grep) {
+				continue
+			}
+		}
+
+		analysis := sub.analyzeBasic(logData)
+		header := ExtractRCAHeader(logData)
+		analysis.Header = header
+		if (analysis.AudioTransport == "" || strings.ToLower(analysis.AudioTransport) == "unknown") && header != nil && header.AudioTransport != "" {
+			analysis.AudioTransport = strings.ToLower(strings.TrimSpace(header.AudioTransport))
+		}
+		metrics := ExtractMetrics(logData)
+
+		agg.CallCount++
+		if header != nil && header.ProviderName != "" {
+			agg.ByProvider[header.ProviderName]++
+		} else {
+			agg.ByProvider["unknown"]++
+		}
+		transport := analysis.AudioTransport
+		if transport == "" {
+			transport = "unknown"
+		}
+		agg.ByTransport[transport]++
+
+		if len(analysis.Errors) > 0 {
+			agg.ByErrorCategory[classifyErrorCategory(analysis.Errors[0])]++
+			signatureCounts[normalizeErrorSignature(analysis.Errors[0])]++
+		}
+
+		if metrics != nil {
+			if metrics.WorstDriftPct != 0 {
+				driftVals = append(driftVals, abs(metrics.WorstDriftPct))
+			}
+			if metrics.UnderflowCount > 0 {
+				underflowVals = append(underflowVals, float64(metrics.UnderflowCount))
+			}
+			if metrics.GateClosures > 0 {
+				gateVals = append(gateVals, float64(metrics.GateClosures))
+			}
+		}
+	}
+
+	agg.DriftPctPercentiles = percentiles(driftVals)
+	agg.UnderflowCountPercentiles = percentiles(underflowVals)
+	agg.GateClosuresPercentiles = percentiles(gateVals)
+	agg.TopFailureReasons = topFailureReasons(signatureCounts, 10)
+
+	if r.jsonOutput {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(agg)
+	}
+
+	displayAggregate(agg)
+	return nil
+}
+
+func percentiles(values []float64) *Percentiles {
+	if len(values) == 0 {
+		return nil
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	return &Percentiles{
+		P50: percentileOf(sorted, 0.50),
+		P90: percentileOf(sorted, 0.90),
+		P99: percentileOf(sorted, 0.99),
+	}
+}
+
+// percentileOf returns the pre-sorted value at index floor(p*(n-1)),
+// without interpolating between neighbours.
+func percentileOf(sorted []float64, p float64) float64 {
+	if len(sorted) == 1 {
+		return sorted[0]
+	}
+	idx := int(p * float64(len(sorted)-1))
+	return sorted[idx]
+}
+
+// timestampPattern strips ISO-8601-ish timestamps and call/channel ids
+// (digits.digits) from an error line so repeated occurrences of the same
+// underlying failure collapse into one signature.
+var (
+	timestampPattern = regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:?\d{2})?`)
+	channelIDPattern = regexp.MustCompile(`\b\d+\.\d+\b`)
+	uuidPattern      = regexp.MustCompile(`\b[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}\b`)
+	numberPattern    = regexp.MustCompile(`\b\d+\b`)
+)
+
+// normalizeErrorSignature strips timestamps/ids/numbers from an error line so
+// the same underlying failure (across different calls) groups into one
+// "top failure reasons" row instead of one row per call.
+func normalizeErrorSignature(line string) string {
+	s := timestampPattern.ReplaceAllString(line, "<ts>")
+	s = uuidPattern.ReplaceAllString(s, "<uuid>")
+	s = channelIDPattern.ReplaceAllString(s, "<id>")
+	s = numberPattern.ReplaceAllString(s, "<n>")
+	return truncate(strings.TrimSpace(s), 160)
+}
+
+func topFailureReasons(counts map[string]int, n int) []FailureReason {
+	reasons := make([]FailureReason, 0, len(counts))
+	for sig, count := range counts {
+		reasons = append(reasons, FailureReason{Signature: sig, Count: count})
+	}
+	sort.Slice(reasons, func(i, j int) bool {
+		if reasons[i].Count != reasons[j].Count {
+			return reasons[i].Count > reasons[j].Count
+		}
+		return reasons[i].Signature < reasons[j].Signature
+	})
+	if len(reasons) > n {
+		reasons = reasons[:n]
+	}
+	return reasons
+}
+
+// classifyErrorCategory buckets the first non-benign error line of a call
+// into a coarse category for the "by error category" breakdown.
+func classifyErrorCategory(line string) string {
+	l := strings.ToLower(line)
+	switch {
+	case strings.Contains(l, "timeout") || strings.Contains(l, "deadline exceeded"):
+		return "timeout"
+	case strings.Contains(l, "websocket") || strings.Contains(l, "connection refused") || strings.Contains(l, "connection reset"):
+		return "connection"
+	case strings.Contains(l, "auth") || strings.Contains(l, "401") || strings.Contains(l, "403"):
+		return "auth"
+	case strings.Contains(l, "format") || strings.Contains(l, "sample rate") || strings.Contains(l, "codec"):
+		return "audio_format"
+	case strings.Contains(l, "ari command failed") || strings.Contains(l, "stasis"):
+		return "asterisk_ari"
+	default:
+		return "other"
+	}
+}
+
+// formatDockerSince converts a time.Duration into the compact unit suffix
+// `docker logs --since` expects (e.g. "24h", "30m"), matching RCA_LOG_SINCE's format.
+func formatDockerSince(d time.Duration) string {
+	if d >= time.Hour && d%time.Hour == 0 {
+		return fmt.Sprintf("%dh", int(d/time.Hour))
+	}
+	if d >= time.Minute && d%time.Minute == 0 {
+		return fmt.Sprintf("%dm", int(d/time.Minute))
+	}
+	return fmt.Sprintf("%ds", int(d/time.Second))
+}
+
+func displayAggregate(agg *RCAAggregate) {
+	fmt.Println()
+	fmt.Println("📊 Cross-Call RCA Stats")
+	fmt.Println("═══════════════════════════════════════════")
+	fmt.Printf("Window: last %s   Calls analyzed: %d\n\n", agg.Since, agg.CallCount)
+
+	printCountTable("By Provider", agg.ByProvider)
+	printCountTable("By Transport", agg.ByTransport)
+	printCountTable("By Error Category", agg.ByErrorCategory)
+
+	printPercentiles("Drift %% (|worst|)", agg.DriftPctPercentiles)
+	printPercentiles("Underflow Count", agg.UnderflowCountPercentiles)
+	printPercentiles("Gate Closures", agg.GateClosuresPercentiles)
+
+	if len(agg.TopFailureReasons) > 0 {
+		fmt.Println("Top Failure Reasons:")
+		for i, reason := range agg.TopFailureReasons {
+			fmt.Printf("  %2d. (%d) %s\n", i+1, reason.Count, reason.Signature)
+		}
+		fmt.Println()
+	}
+}
+
+func printCountTable(title string, counts map[string]int) {
+	if len(counts) == 0 {
+		return
+	}
+	type row struct {
+		key   string
+		count int
+	}
+	rows := make([]row, 0, len(counts))
+	for k, v := range counts {
+		rows = append(rows, row{k, v})
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].count > rows[j].count })
+
+	fmt.Printf("%s:\n", title)
+	for _, r := range rows {
+		fmt.Printf("  %-20s %d\n", r.key, r.count)
+	}
+	fmt.Println()
+}
+
+func printPercentiles(title string, p *Percentiles) {
+	if p == nil {
+		return
+	}
+	fmt.Printf("%s:  p50=%.1f  p90=%.1f  p99=%.1f\n\n", title, p.P50, p.P90, p.P99)
+}
@@ -0,0 +1,114 @@
+package store
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Filter is a parsed `agent rca history --filter`/`agent rca query`
+// expression, e.g.
+// `provider=deepgram transport=externalmedia symptom=barge_in drift_pct>5 since=24h grep="underflow"`.
+type Filter struct {
+	Provider  string
+	Transport string
+	Symptom   string
+	Since     time.Duration
+	Grep      string
+	Numeric   []NumericFilter
+}
+
+// NumericFilter is one `field<op><value>` comparison, e.g. `drift_pct>5`.
+type NumericFilter struct {
+	Field string
+	Op    string
+	Value float64
+}
+
+var tokenOps = []string{">=", "<=", "!=", ">", "<", "="}
+
+// ParseQuery parses the small filter DSL: space-separated key=value (or
+// key>value/key<value/...) pairs, with double-quoted values allowed to
+// contain spaces. Recognized keys are provider/transport/symptom/since/grep;
+// anything else is treated as a numeric comparison against a metrics column
+// (drift_pct, underflow_count, gate_closures) and rejected by Store.Query if
+// it doesn't match one.
+//
+// latency_ms and other per-event-latency filters are intentionally not
+// supported here: the log pipeline doesn't emit barge-in/first-audio/
+// TTS-start latency events yet (see RCAAggregate in the troubleshoot
+// package for the same caveat).
+func ParseQuery(raw string) (Filter, error) {
+	var f Filter
+	tokens, err := tokenize(raw)
+	if err != nil {
+		return f, err
+	}
+	for _, tok := range tokens {
+		key, op, value, err := splitToken(tok)
+		if err != nil {
+			return f, err
+		}
+		switch key {
+		case "provider":
+			f.Provider = value
+		case "transport":
+			f.Transport = value
+		case "symptom":
+			f.Symptom = value
+		case "grep":
+			f.Grep = value
+		case "since":
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return f, fmt.Errorf("invalid since duration %q: %w", value, err)
+			}
+			f.Since = d
+		default:
+			num, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return f, fmt.Errorf("%q is not a recognized filter key and not a numeric comparison: %w", tok, err)
+			}
+			f.Numeric = append(f.Numeric, NumericFilter{Field: key, Op: op, Value: num})
+		}
+	}
+	return f, nil
+}
+
+// tokenize splits raw on unquoted whitespace, keeping double-quoted values
+// (which may contain spaces) intact.
+func tokenize(raw string) ([]string, error) {
+	var tokens []string
+	var cur strings.Builder
+	inQuotes := false
+	for _, r := range raw {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+		case r == ' ' && !inQuotes:
+			if cur.Len() > 0 {
+				tokens = append(tokens, cur.String())
+				cur.Reset()
+			}
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	if inQuotes {
+		return nil, fmt.Errorf("unterminated quote in query")
+	}
+	if cur.Len() > 0 {
+		tokens = append(tokens, cur.String())
+	}
+	return tokens, nil
+}
+
+func splitToken(tok string) (key, op, value string, err error) {
+	for _, candidate := range tokenOps {
+		if idx := strings.Index(tok, candidate); idx > 0 {
+			return tok[:idx], candidate, tok[idx+len(candidate):], nil
+		}
+	}
+	return "", "", "", fmt.Errorf("invalid filter token %q (expected key=value, key>value, ...)", tok)
+}
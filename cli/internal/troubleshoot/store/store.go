@@ -0,0 +1,212 @@
+// Package store persists generated RCA reports (plus the raw filtered log
+// slice they were built from) in a local SQLite database, so `agent rca
+// history`/`agent rca query` can answer "find all calls with X problem in
+// the last N days" or re-render an old report even after the original
+// ai_engine logs have rotated away.
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// Record is one persisted call: the RCAReport (JSON-encoded by the caller,
+// to avoid an import cycle on troubleshoot.RCAReport) plus the raw filtered
+// log slice it was generated from, and the handful of fields the query DSL
+// filters on.
+type Record struct {
+	CallID         string
+	ProviderName   string
+	AudioTransport string
+	Symptom        string
+	DriftPct       float64
+	UnderflowCount int
+	GateClosures   int
+	CreatedAt      time.Time
+	ReportJSON     string
+	LogData        string
+	ErrorsText     string
+}
+
+// Summary is what Query returns per matching call; fetch the full Record via
+// Get to re-render a report.
+type Summary struct {
+	CallID         string
+	ProviderName   string
+	AudioTransport string
+	Symptom        string
+	DriftPct       float64
+	UnderflowCount int
+	GateClosures   int
+	CreatedAt      time.Time
+	ErrorsText     string
+}
+
+// Store wraps the SQLite database backing `agent rca history`/`agent rca
+// query`.
+type Store struct {
+	db *sql.DB
+}
+
+// Open creates (if needed) and opens the SQLite database at path.
+func Open(path string) (*Store, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("open store: %w", err)
+	}
+	if err := migrate(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrate store: %w", err)
+	}
+	return &Store{db: db}, nil
+}
+
+func migrate(db *sql.DB) error {
+	_, err := db.Exec(`
+CREATE TABLE IF NOT EXISTS rca_reports (
+	call_id         TEXT PRIMARY KEY,
+	provider_name   TEXT NOT NULL DEFAULT '',
+	audio_transport TEXT NOT NULL DEFAULT '',
+	symptom         TEXT NOT NULL DEFAULT '',
+	drift_pct       REAL NOT NULL DEFAULT 0,
+	underflow_count INTEGER NOT NULL DEFAULT 0,
+	gate_closures   INTEGER NOT NULL DEFAULT 0,
+	created_at      TIMESTAMP NOT NULL,
+	report_json     TEXT NOT NULL,
+	log_data        TEXT NOT NULL DEFAULT '',
+	errors_text     TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS idx_rca_reports_provider   ON rca_reports(provider_name);
+CREATE INDEX IF NOT EXISTS idx_rca_reports_transport  ON rca_reports(audio_transport);
+CREATE INDEX IF NOT EXISTS idx_rca_reports_symptom    ON rca_reports(symptom);
+CREATE INDEX IF NOT EXISTS idx_rca_reports_created_at ON rca_reports(created_at);
+CREATE VIRTUAL TABLE IF NOT EXISTS rca_reports_fts USING fts5(
+	call_id UNINDEXED, errors_text
+);
+`)
+	return err
+}
+
+// Close closes the underlying database handle.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Save upserts one call's report. Callers should treat failures as
+// best-effort (log and continue) rather than fail the RCA run over it.
+func (s *Store) Save(rec Record) error {
+	if rec.CreatedAt.IsZero() {
+		rec.CreatedAt = time.Now()
+	}
+	_, err := s.db.Exec(`
+INSERT INTO rca_reports (call_id, provider_name, audio_transport, symptom, drift_pct, underflow_count, gate_closures, created_at, report_json, log_data, errors_text)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(call_id) DO UPDATE SET
+	provider_name=excluded.provider_name,
+	audio_transport=excluded.audio_transport,
+	symptom=excluded.symptom,
+	drift_pct=excluded.drift_pct,
+	underflow_count=excluded.underflow_count,
+	gate_closures=excluded.gate_closures,
+	created_at=excluded.created_at,
+	report_json=excluded.report_json,
+	log_data=excluded.log_data,
+	errors_text=excluded.errors_text
+`, rec.CallID, rec.ProviderName, rec.AudioTransport, rec.Symptom, rec.DriftPct, rec.UnderflowCount, rec.GateClosures, rec.CreatedAt, rec.ReportJSON, rec.LogData, rec.ErrorsText)
+	if err != nil {
+		return err
+	}
+	if _, err := s.db.Exec(`DELETE FROM rca_reports_fts WHERE call_id = ?`, rec.CallID); err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`INSERT INTO rca_reports_fts (call_id, errors_text) VALUES (?, ?)`, rec.CallID, rec.ErrorsText)
+	return err
+}
+
+// Get re-renders a stored report by call_id, without needing the original
+// ai_engine logs (which may have rotated away).
+func (s *Store) Get(callID string) (*Record, error) {
+	row := s.db.QueryRow(`
+SELECT call_id, provider_name, audio_transport, symptom, drift_pct, underflow_count, gate_closures, created_at, report_json, log_data, errors_text
+FROM rca_reports WHERE call_id = ?`, callID)
+	var rec Record
+	if err := row.Scan(&rec.CallID, &rec.ProviderName, &rec.AudioTransport, &rec.Symptom, &rec.DriftPct, &rec.UnderflowCount, &rec.GateClosures, &rec.CreatedAt, &rec.ReportJSON, &rec.LogData, &rec.ErrorsText); err != nil {
+		return nil, err
+	}
+	return &rec, nil
+}
+
+// numericColumns maps the query DSL's numeric filter fields (e.g.
+// `drift_pct>5`) to the real column name. Kept as its own table so
+// Query rejects unsupported fields (like latency_ms, which the log
+// pipeline doesn't emit yet) instead of silently matching nothing.
+var numericColumns = map[string]string{
+	"drift_pct":       "drift_pct",
+	"underflow_count": "underflow_count",
+	"gate_closures":   "gate_closures",
+}
+
+var sqlOps = map[string]bool{">": true, "<": true, ">=": true, "<=": true, "=": true, "!=": true}
+
+// Query returns summaries for every call matching f, most recent first.
+func (s *Store) Query(f Filter) ([]Summary, error) {
+	where := []string{"1=1"}
+	var args []any
+
+	if f.Provider != "" {
+		where = append(where, "provider_name = ?")
+		args = append(args, f.Provider)
+	}
+	if f.Transport != "" {
+		where = append(where, "audio_transport = ?")
+		args = append(args, f.Transport)
+	}
+	if f.Symptom != "" {
+		where = append(where, "symptom = ?")
+		args = append(args, f.Symptom)
+	}
+	if f.Since > 0 {
+		where = append(where, "created_at >= ?")
+		args = append(args, time.Now().Add(-f.Since))
+	}
+	for _, nf := range f.Numeric {
+		col, ok := numericColumns[nf.Field]
+		if !ok {
+			return nil, fmt.Errorf("unsupported filter field %q (supported: provider, transport, symptom, since, grep, drift_pct, underflow_count, gate_closures)", nf.Field)
+		}
+		if !sqlOps[nf.Op] {
+			return nil, fmt.Errorf("unsupported operator %q", nf.Op)
+		}
+		where = append(where, fmt.Sprintf("%s %s ?", col, nf.Op))
+		args = append(args, nf.Value)
+	}
+
+	selectCols := "r.call_id, r.provider_name, r.audio_transport, r.symptom, r.drift_pct, r.underflow_count, r.gate_closures, r.created_at, r.errors_text"
+	query := "SELECT " + selectCols + " FROM rca_reports r"
+	if f.Grep != "" {
+		query += " JOIN rca_reports_fts fts ON fts.call_id = r.call_id"
+		where = append(where, "rca_reports_fts MATCH ?")
+		args = append(args, f.Grep)
+	}
+	query += " WHERE " + strings.Join(where, " AND ") + " ORDER BY r.created_at DESC"
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Summary
+	for rows.Next() {
+		var sum Summary
+		if err := rows.Scan(&sum.CallID, &sum.ProviderName, &sum.AudioTransport, &sum.Symptom, &sum.DriftPct, &sum.UnderflowCount, &sum.GateClosures, &sum.CreatedAt, &sum.ErrorsText); err != nil {
+			return nil, err
+		}
+		out = append(out, sum)
+	}
+	return out, rows.Err()
+}
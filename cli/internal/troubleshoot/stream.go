@@ -0,0 +1,278 @@
+package troubleshoot
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// MetricsSink receives parsed RCA events as they are discovered in the log
+// stream. aggregatorSink folds events into a *CallMetrics the same way
+// ExtractMetrics does for a finished call; StreamingSink instead forwards
+// each event over HTTP as it arrives, for in-progress calls.
+type MetricsSink interface {
+	OnProviderSegment(seg ProviderSegment)
+	OnStreamingSummary(sum StreamingSummary)
+	OnTransportAlignment(audioSocketFormat, providerInputFormat, providerOutputFormat string, sampleRate int)
+	OnVADSettings(v VADSettings)
+	OnGateClosure()
+	OnUnderflow(count int)
+}
+
+// aggregatorSink is the default sink: it folds events into a *CallMetrics,
+// exactly like ExtractMetrics did before sinks existed.
+type aggregatorSink struct {
+	metrics *CallMetrics
+}
+
+func (a *aggregatorSink) OnProviderSegment(seg ProviderSegment) {
+	if seg.ProviderBytes > 0 {
+		a.metrics.ProviderBytesTotal += seg.ProviderBytes
+	}
+	if seg.EnqueuedBytes > 0 {
+		a.metrics.EnqueuedBytesTotal += seg.EnqueuedBytes
+	}
+	if seg.Ratio != 0 {
+		deviation := abs(1.0 - seg.Ratio)
+		worstDeviation := abs(1.0 - a.metrics.WorstEnqueuedRatio)
+		if deviation > worstDeviation {
+			a.metrics.WorstEnqueuedRatio = seg.Ratio
+		}
+	}
+	a.metrics.ProviderSegments = append(a.metrics.ProviderSegments, seg)
+}
+
+func (a *aggregatorSink) OnStreamingSummary(sum StreamingSummary) {
+	if sum.DriftPct != 0 && !sum.IsGreeting {
+		if abs(sum.DriftPct) > abs(a.metrics.WorstDriftPct) {
+			a.metrics.WorstDriftPct = sum.DriftPct
+		}
+	}
+	a.metrics.StreamingSummaries = append(a.metrics.StreamingSummaries, sum)
+}
+
+func (a *aggregatorSink) OnTransportAlignment(audioSocketFormat, providerInputFormat, providerOutputFormat string, sampleRate int) {
+	if audioSocketFormat != "" {
+		a.metrics.AudioSocketFormat = audioSocketFormat
+	}
+	if providerInputFormat != "" {
+		a.metrics.ProviderInputFormat = providerInputFormat
+	}
+	if providerOutputFormat != "" {
+		a.metrics.ProviderOutputFormat = providerOutputFormat
+	}
+	if sampleRate > 0 {
+		a.metrics.SampleRate = sampleRate
+	}
+}
+
+func (a *aggregatorSink) OnVADSettings(v VADSettings) {
+	a.metrics.VADSettings = &v
+}
+
+func (a *aggregatorSink) OnGateClosure() {
+	a.metrics.GateClosures++
+	if a.metrics.GateClosures > 50 {
+		a.metrics.GateFlutterDetected = true
+	}
+}
+
+func (a *aggregatorSink) OnUnderflow(count int) {
+	a.metrics.UnderflowCount += count
+}
+
+// StreamEvent is the NDJSON/SSE wire shape emitted by StreamingSink.
+type StreamEvent struct {
+	Type      string      `json:"type"`
+	Timestamp time.Time   `json:"timestamp"`
+	Data      interface{} `json:"data,omitempty"`
+}
+
+// HeartbeatData carries running totals so a dashboard can plot drift/
+// underflow progression before the call ends.
+type HeartbeatData struct {
+	ProviderBytesTotal int     `json:"provider_bytes_total"`
+	UnderflowCount     int     `json:"underflow_count"`
+	WorstDriftPct      float64 `json:"worst_drift_pct"`
+}
+
+// StreamingSink writes each parsed event to an HTTP response as NDJSON
+// (one JSON object per line) and flushes immediately so a live tail can
+// follow an in-progress call instead of waiting for teardown.
+type StreamingSink struct {
+	w       io.Writer
+	flusher http.Flusher
+	metrics *CallMetrics
+}
+
+// NewStreamingSink wraps the given response writer. If w also implements
+// http.Flusher, each event is flushed as soon as it is written.
+func NewStreamingSink(w io.Writer) *StreamingSink {
+	s := &StreamingSink{w: w, metrics: &CallMetrics{WorstEnqueuedRatio: 1.0}}
+	if f, ok := w.(http.Flusher); ok {
+		s.flusher = f
+	}
+	return s
+}
+
+func (s *StreamingSink) emit(eventType string, data interface{}) {
+	ev := StreamEvent{Type: eventType, Timestamp: time.Now(), Data: data}
+	b, err := json.Marshal(ev)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(s.w, "%s\n", b)
+	if s.flusher != nil {
+		s.flusher.Flush()
+	}
+}
+
+func (s *StreamingSink) OnProviderSegment(seg ProviderSegment) {
+	s.metrics.ProviderBytesTotal += seg.ProviderBytes
+	s.emit("provider_segment", seg)
+}
+
+func (s *StreamingSink) OnStreamingSummary(sum StreamingSummary) {
+	if !sum.IsGreeting && abs(sum.DriftPct) > abs(s.metrics.WorstDriftPct) {
+		s.metrics.WorstDriftPct = sum.DriftPct
+	}
+	s.emit("streaming_summary", sum)
+}
+
+func (s *StreamingSink) OnTransportAlignment(audioSocketFormat, providerInputFormat, providerOutputFormat string, sampleRate int) {
+	s.emit("transport_alignment", map[string]interface{}{
+		"audiosocket_format":     audioSocketFormat,
+		"provider_input_format":  providerInputFormat,
+		"provider_output_format": providerOutputFormat,
+		"sample_rate":            sampleRate,
+	})
+}
+
+func (s *StreamingSink) OnVADSettings(v VADSettings) {
+	s.emit("vad_settings", v)
+}
+
+func (s *StreamingSink) OnGateClosure() {
+	s.emit("gate_closure", nil)
+}
+
+func (s *StreamingSink) OnUnderflow(count int) {
+	s.metrics.UnderflowCount += count
+	s.emit("underflow", map[string]int{"count": count})
+}
+
+// Heartbeat emits the sink's running totals. Callers typically invoke this
+// on a ticker while a --follow tail is active.
+func (s *StreamingSink) Heartbeat() {
+	s.emit("heartbeat", HeartbeatData{
+		ProviderBytesTotal: s.metrics.ProviderBytesTotal,
+		UnderflowCount:     s.metrics.UnderflowCount,
+		WorstDriftPct:      s.metrics.WorstDriftPct,
+	})
+}
+
+// extractMetricsToSink walks logData once, dispatching each recognized RCA
+// event into sink. It mirrors the console-log parsing ExtractMetrics does in
+// metrics.go; ServeDiagnosticsStream uses it with a StreamingSink so a live
+// tail can see events as they're parsed instead of waiting for call teardown.
+func extractMetricsToSink(logData string, sink MetricsSink, metrics *CallMetrics) {
+	lines := strings.Split(logData, "\n")
+
+	for _, line := range lines {
+		_, event, fields, ok := parseLogLine(line)
+		if !ok {
+			continue
+		}
+
+		switch event {
+		case "PROVIDER SEGMENT BYTES":
+			if len(fields) > 0 {
+				seg := ProviderSegment{
+					ProviderBytes: atoiSafe(fields["provider_bytes"]),
+					EnqueuedBytes: atoiSafe(fields["enqueued_bytes"]),
+				}
+				if v := fields["enqueued_ratio"]; v != "" {
+					seg.Ratio = atofSafe(v)
+				}
+				sink.OnProviderSegment(seg)
+			}
+
+		case "üéõÔ∏è STREAMING TUNING SUMMARY":
+			if len(fields) > 0 {
+				sid := fields["stream_id"]
+				sink.OnStreamingSummary(StreamingSummary{
+					StreamID:         sid,
+					IsGreeting:       strings.Contains(sid, "greeting"),
+					BytesSent:        atoiSafe(fields["bytes_sent"]),
+					EffectiveSeconds: atofSafe(fields["effective_seconds"]),
+					WallSeconds:      atofSafe(fields["wall_seconds"]),
+					DriftPct:         atofSafe(fields["drift_pct"]),
+					LowWatermark:     atoiSafe(fields["low_watermark"]),
+					MinStart:         atoiSafe(fields["min_start"]),
+				})
+			}
+
+		case "Transport alignment summary":
+			if len(fields) > 0 {
+				sink.OnTransportAlignment(fields["audiosocket_format"], fields["provider_input_format"], fields["provider_output_format"], atoiSafe(fields["sample_rate"]))
+			}
+
+		case "üéØ WebRTC VAD settings":
+			if len(fields) > 0 {
+				agg := fields["aggressiveness"]
+				if agg == "" {
+					agg = fields["webrtc_aggressiveness"]
+				}
+				sink.OnVADSettings(VADSettings{
+					WebRTCAggressiveness: atoiSafe(agg),
+					ConfidenceThreshold:  atofSafe(fields["confidence_threshold"]),
+					EnergyThreshold:      atoiSafe(fields["energy_threshold"]),
+					EnhancedEnabled:      strings.ToLower(strings.TrimSpace(fields["enhanced_enabled"])) == "true",
+				})
+			}
+
+		case "Streaming segment bytes summary v2":
+			streamID := fields["stream_id"]
+			isGreeting := strings.Contains(streamID, "greeting")
+			if underflows := atoiSafe(fields["underflow_events"]); underflows > 0 && !isGreeting {
+				sink.OnUnderflow(underflows)
+			}
+
+		default:
+			if strings.Contains(event, "gate_closure") {
+				sink.OnGateClosure()
+			}
+			if metrics != nil && strings.Contains(line, "target_encoding") && strings.Contains(line, "error") && !strings.Contains(line, "DeepgramProviderConfig") {
+				metrics.ConfigErrors = append(metrics.ConfigErrors, "Configuration error related to target_encoding")
+			}
+		}
+	}
+}
+
+// streamTriggerHeader opts an HTTP request into the live NDJSON feed.
+// Absent, ServeDiagnosticsStream falls back to the one-shot FormatForLLM text.
+const streamTriggerHeader = "X-Troubleshoot-Stream"
+
+// ServeDiagnosticsStream serves live call diagnostics over HTTP. With the
+// X-Troubleshoot-Stream header set, logData is parsed once and every
+// recognized event is flushed to the response as NDJSON, with a heartbeat
+// after the final line carrying running totals. Without the header, it
+// falls back to the existing one-shot FormatForLLM text report.
+func ServeDiagnosticsStream(w http.ResponseWriter, r *http.Request, logData string) {
+	if strings.TrimSpace(r.Header.Get(streamTriggerHeader)) == "" {
+		metrics := ExtractMetrics(logData)
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprint(w, metrics.FormatForLLM())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+
+	sink := NewStreamingSink(w)
+	extractMetricsToSink(logData, sink, nil)
+	sink.Heartbeat()
+}
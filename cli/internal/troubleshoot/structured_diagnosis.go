@@ -0,0 +1,125 @@
+package troubleshoot
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// FormatForDisplay renders a StructuredDiagnosis as the free-text report
+// operators saw before structured output existed, so existing callers of
+// LLMDiagnosis.Analysis (CLI display, JSON report) keep working unchanged.
+func (d *StructuredDiagnosis) FormatForDisplay() string {
+	if d == nil {
+		return ""
+	}
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("Root Cause: %s\n", d.RootCause))
+	out.WriteString(fmt.Sprintf("Confidence: %s\n", d.Confidence))
+	if d.Severity != "" {
+		out.WriteString(fmt.Sprintf("Severity: %s\n", d.Severity))
+	}
+	if len(d.ConfigChanges) > 0 {
+		out.WriteString("Quick Fix:\n")
+		for _, c := range d.ConfigChanges {
+			out.WriteString(fmt.Sprintf("  - %s [%s] %s: %s -> %s\n", c.File, c.Section, c.Key, c.CurrentValue, c.ExpectedValue))
+		}
+	} else {
+		out.WriteString("Quick Fix: N/A\n")
+	}
+	if len(d.PreventionSteps) > 0 {
+		out.WriteString("Prevention:\n")
+		for _, step := range d.PreventionSteps {
+			out.WriteString(fmt.Sprintf("  - %s\n", step))
+		}
+	}
+	return out.String()
+}
+
+// StructuredDiagnosis is the machine-readable form of an LLM diagnosis. It
+// lets a downstream subsystem apply ConfigChange entries to
+// config/ai-agent.yaml without another LLM pass, instead of regex-scraping
+// LLMDiagnosis.Analysis for Root Cause / Confidence / Quick Fix / Prevention.
+type StructuredDiagnosis struct {
+	RootCause       string         `json:"root_cause"`
+	Confidence      string         `json:"confidence"` // High, Medium, Low
+	Severity        string         `json:"severity"`   // critical, warning, info
+	ConfigChanges   []ConfigChange `json:"config_changes"`
+	PreventionSteps []string       `json:"prevention_steps"`
+	EvidenceRefs    []string       `json:"evidence_refs"`
+}
+
+// ConfigChange identifies a single config/ai-agent.yaml edit recommended by
+// the diagnosis.
+type ConfigChange struct {
+	File          string `json:"file"`
+	Section       string `json:"section"`
+	Key           string `json:"key"`
+	CurrentValue  string `json:"current_value"`
+	ExpectedValue string `json:"expected_value"`
+}
+
+// emitDiagnosisSchema is the JSON Schema passed to OpenAI-shaped
+// response_format / tool definitions so the model returns a StructuredDiagnosis
+// directly instead of free text.
+const emitDiagnosisSchema = `{
+  "type": "object",
+  "properties": {
+    "root_cause": {"type": "string"},
+    "confidence": {"type": "string", "enum": ["High", "Medium", "Low"]},
+    "severity": {"type": "string", "enum": ["critical", "warning", "info"]},
+    "config_changes": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "properties": {
+          "file": {"type": "string"},
+          "section": {"type": "string"},
+          "key": {"type": "string"},
+          "current_value": {"type": "string"},
+          "expected_value": {"type": "string"}
+        },
+        "required": ["file", "section", "key", "expected_value"]
+      }
+    },
+    "prevention_steps": {"type": "array", "items": {"type": "string"}},
+    "evidence_refs": {"type": "array", "items": {"type": "string"}}
+  },
+  "required": ["root_cause", "confidence", "severity"]
+}`
+
+// structuredDiagnosisInstructions is appended to buildPrompt's free-text
+// prompt so every provider (including ones using a tools/function-call path)
+// knows what shape to fill in.
+const structuredDiagnosisInstructions = "\nRespond ONLY by calling emit_diagnosis with root_cause, confidence, severity, config_changes (file/section/key/current_value/expected_value), prevention_steps, and evidence_refs populated from the evidence above. Do not include any other text."
+
+// jsonObjectPattern spans from the first '{' to the last '}' in a string, used
+// as a fallback when a provider ignores response_format/tools and just
+// returns JSON (or JSON wrapped in prose/markdown fences).
+var jsonObjectPattern = regexp.MustCompile(`(?s)\{.*\}`)
+
+// parseStructuredDiagnosis extracts a StructuredDiagnosis from raw model
+// output. raw is normally the arguments of an emit_diagnosis tool call, but
+// this also tolerates a bare JSON object for providers that only support
+// response_format.
+func parseStructuredDiagnosis(raw string) (*StructuredDiagnosis, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, fmt.Errorf("empty diagnosis payload")
+	}
+
+	var d StructuredDiagnosis
+	if err := json.Unmarshal([]byte(raw), &d); err == nil {
+		return &d, nil
+	}
+
+	match := jsonObjectPattern.FindString(raw)
+	if match == "" {
+		return nil, fmt.Errorf("no JSON object found in diagnosis payload")
+	}
+	if err := json.Unmarshal([]byte(match), &d); err != nil {
+		return nil, fmt.Errorf("failed to parse diagnosis payload: %w", err)
+	}
+	return &d, nil
+}
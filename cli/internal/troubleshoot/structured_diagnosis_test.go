@@ -0,0 +1,45 @@
+package troubleshoot
+
+import "testing"
+
+func TestParseStructuredDiagnosisFromToolCallArguments(t *testing.T) {
+	t.Parallel()
+
+	// Recorded shape of an OpenAI/Azure tool_calls[0].function.arguments string.
+	raw := `{"root_cause":"Sample rate mismatch between config (16000) and provider (24000)","confidence":"High","severity":"critical","config_changes":[{"file":"config/ai-agent.yaml","section":"providers.google_live","key":"output_sample_rate_hz","current_value":"16000","expected_value":"24000"}],"prevention_steps":["Validate provider sample rate against config on startup"],"evidence_refs":["drift_pct=38.2"]}`
+
+	d, err := parseStructuredDiagnosis(raw)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d.RootCause == "" || d.Confidence != "High" || d.Severity != "critical" {
+		t.Fatalf("unexpected diagnosis: %+v", d)
+	}
+	if len(d.ConfigChanges) != 1 || d.ConfigChanges[0].Key != "output_sample_rate_hz" {
+		t.Fatalf("unexpected config changes: %+v", d.ConfigChanges)
+	}
+}
+
+func TestParseStructuredDiagnosisFromGatewayThatIgnoresToolChoice(t *testing.T) {
+	t.Parallel()
+
+	// Some OpenAI-compatible gateways (LocalAI/Ollama) echo JSON back as
+	// plain message content, sometimes wrapped in prose or markdown fences.
+	raw := "Here is the diagnosis:\n```json\n{\"root_cause\":\"Gate flutter from aggressive VAD\",\"confidence\":\"Medium\",\"severity\":\"warning\"}\n```\n"
+
+	d, err := parseStructuredDiagnosis(raw)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d.RootCause != "Gate flutter from aggressive VAD" || d.Confidence != "Medium" {
+		t.Fatalf("unexpected diagnosis: %+v", d)
+	}
+}
+
+func TestParseStructuredDiagnosisEmptyPayload(t *testing.T) {
+	t.Parallel()
+
+	if _, err := parseStructuredDiagnosis("   "); err == nil {
+		t.Fatalf("expected error for empty payload")
+	}
+}
@@ -0,0 +1,168 @@
+package troubleshoot
+
+import (
+	"regexp"
+	"strings"
+)
+
+// timestampRe matches a leading RFC3339-ish timestamp, the shape
+// ai_engine's console logger prefixes every line with (e.g.
+// "2026-01-30T12:00:00.000000-07:00").
+var timestampRe = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?([+-]\d{2}:\d{2}|Z)?`)
+
+// ParseStructured is a stricter replacement for the console half of
+// parseLogLine's key=value scanning: a small state-machine tokenizer
+// instead of a single regex, so a stray '=' inside a quoted reason field
+// (e.g. reason='{"message":"..."}') can't corrupt adjacent keys, and a
+// quoted value may itself contain escaped quotes or a balanced nested
+// JSON object/array. It also surfaces the leading timestamp, which
+// parseLogLine discards.
+//
+// JSON lines are handled by delegating to parseLogLine's existing JSON
+// fast path, so ai_engine can emit either console or JSON logs and callers
+// of ParseStructured don't need two code paths.
+func ParseStructured(line string) (ts, level, event string, fields map[string]string, ok bool) {
+	if m := timestampRe.FindString(line); m != "" {
+		ts = m
+	}
+
+	trimmed := strings.TrimSpace(line)
+	if strings.HasPrefix(trimmed, "{") {
+		level, event, fields, ok = parseLogLine(line)
+		return ts, level, event, fields, ok
+	}
+
+	level, event, fields, ok = parseLogLine(line)
+	if !ok {
+		return ts, level, event, fields, ok
+	}
+	fields = tokenizeKeyValues(line)
+	return ts, level, event, fields, ok
+}
+
+// tokenizeKeyValues scans line for key=value pairs using an explicit
+// state machine rather than a single regex, so it can correctly consume:
+//   - single- or double-quoted values containing the other quote style
+//     or an escaped version of their own quote (\' or \")
+//   - a nested JSON object/value as an opaque value (reason={"a":1,"b"="x"}),
+//     tracked via brace/bracket depth so an embedded '=' or quote doesn't
+//     truncate the value early
+//   - plain unquoted values terminated by whitespace
+func tokenizeKeyValues(line string) map[string]string {
+	fields := make(map[string]string, 16)
+	i, n := 0, len(line)
+
+	isKeyStart := func(c byte) bool {
+		return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+	}
+	isKeyChar := func(c byte) bool {
+		return isKeyStart(c) || (c >= '0' && c <= '9')
+	}
+
+	for i < n {
+		// Skip to the next plausible key start.
+		for i < n && (line[i] == ' ' || line[i] == '\t' || !isKeyStart(line[i])) {
+			i++
+		}
+		start := i
+		for i < n && isKeyChar(line[i]) {
+			i++
+		}
+		if i >= n || line[i] != '=' || i == start {
+			continue
+		}
+		key := line[start:i]
+		i++ // consume '='
+
+		if i >= n {
+			fields[key] = ""
+			break
+		}
+
+		var value string
+		switch line[i] {
+		case '\'', '"':
+			value, i = scanQuoted(line, i)
+		case '{', '[':
+			value, i = scanBalanced(line, i)
+		default:
+			valStart := i
+			for i < n && line[i] != ' ' && line[i] != '\t' {
+				i++
+			}
+			value = line[valStart:i]
+		}
+		fields[key] = value
+	}
+	return fields
+}
+
+// scanQuoted consumes a '...' or "..." value starting at quote (line[quote]
+// is the opening quote), honoring backslash-escaped quotes, and returns the
+// unquoted content plus the index just past the closing quote.
+func scanQuoted(line string, quote int) (string, int) {
+	q := line[quote]
+	var b strings.Builder
+	i := quote + 1
+	for i < len(line) {
+		c := line[i]
+		if c == '\\' && i+1 < len(line) && (line[i+1] == q || line[i+1] == '\\') {
+			b.WriteByte(line[i+1])
+			i += 2
+			continue
+		}
+		if c == q {
+			i++
+			break
+		}
+		b.WriteByte(c)
+		i++
+	}
+	return b.String(), i
+}
+
+// scanBalanced consumes a {...} or [...] value starting at open,
+// respecting nested brace/bracket depth and quoted strings inside (so a
+// '}' or '=' inside a quoted JSON string value doesn't end the scan
+// early), and returns the raw JSON text plus the index just past it.
+func scanBalanced(line string, open int) (string, int) {
+	openCh := line[open]
+	closeCh := byte('}')
+	if openCh == '[' {
+		closeCh = ']'
+	}
+	depth := 0
+	i := open
+	inString := false
+	for i < len(line) {
+		c := line[i]
+		if inString {
+			if c == '\\' {
+				i += 2
+				continue
+			}
+			if c == '"' {
+				inString = false
+			}
+			i++
+			continue
+		}
+		switch c {
+		case '"':
+			inString = true
+		case openCh:
+			depth++
+		case closeCh:
+			depth--
+			if depth == 0 {
+				i++
+				return line[open:i], i
+			}
+		}
+		i++
+	}
+	if i > len(line) {
+		i = len(line) // a trailing backslash inside a string skipped past the end
+	}
+	return line[open:i], i
+}
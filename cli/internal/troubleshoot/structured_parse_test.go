@@ -0,0 +1,78 @@
+package troubleshoot
+
+import "testing"
+
+func TestParseStructuredQuotedReasonWithEmbeddedJSON(t *testing.T) {
+	t.Parallel()
+
+	line := "[error    ] ARI command failed [src.ari_client] component=src.ari_client method=GET reason='{\"message\":\"Provided variable was not found\"}' service=ai-engine status=404"
+	_, level, _, fields, ok := ParseStructured(line)
+	if !ok {
+		t.Fatalf("expected parse to succeed")
+	}
+	if level != "error" {
+		t.Fatalf("level=%q", level)
+	}
+	if fields["reason"] != `{"message":"Provided variable was not found"}` {
+		t.Fatalf("reason=%q", fields["reason"])
+	}
+	if fields["service"] != "ai-engine" || fields["status"] != "404" {
+		t.Fatalf("service=%q status=%q", fields["service"], fields["status"])
+	}
+}
+
+func TestParseStructuredRCAHeaderLineWithTimestamp(t *testing.T) {
+	t.Parallel()
+
+	line := "2026-01-30T12:00:00.000000-07:00 [info     ] RCA_CALL_START [src.engine] call_id=1769799752.1415 audio_transport=externalmedia"
+	ts, _, event, fields, ok := ParseStructured(line)
+	if !ok {
+		t.Fatalf("expected parse to succeed")
+	}
+	if ts != "2026-01-30T12:00:00.000000-07:00" {
+		t.Fatalf("ts=%q", ts)
+	}
+	if event != "RCA_CALL_START" {
+		t.Fatalf("event=%q", event)
+	}
+	if fields["call_id"] != "1769799752.1415" || fields["audio_transport"] != "externalmedia" {
+		t.Fatalf("call_id=%q audio_transport=%q", fields["call_id"], fields["audio_transport"])
+	}
+}
+
+func TestParseStructuredNestedJSONWithEmbeddedEquals(t *testing.T) {
+	t.Parallel()
+
+	line := `[info     ] tool call [src.tools] args={"filter":"a=b","nested":{"x":1}} ok=true`
+	_, _, _, fields, ok := ParseStructured(line)
+	if !ok {
+		t.Fatalf("expected parse to succeed")
+	}
+	if fields["args"] != `{"filter":"a=b","nested":{"x":1}}` {
+		t.Fatalf("args=%q", fields["args"])
+	}
+	if fields["ok"] != "true" {
+		t.Fatalf("ok=%q", fields["ok"])
+	}
+}
+
+// FuzzParseStructured seeds the tokenizer with real RCA headers, benign
+// ARI 404 lines, and multi-line stack traces, and just asserts it never
+// panics — correctness of specific fields is covered by the table tests
+// above.
+func FuzzParseStructured(f *testing.F) {
+	seeds := []string{
+		"2026-01-30T12:00:00.000000-07:00 [info     ] RCA_CALL_START [src.engine] call_id=1769799752.1415 caller_number=15555550123 audio_transport=externalmedia tp_encoding=ulaw tp_sample_rate=8000",
+		"[error    ] ARI command failed [src.ari_client] component=src.ari_client method=GET reason='{\"message\":\"Provided variable was not found\"}' service=ai-engine status=404 url=https://127.0.0.1:8089/ari/channels/1769719558.1020/variable",
+		"Traceback (most recent call last):\n  File \"engine.py\", line 42, in run\nValueError: bad frame size=320",
+		`{"event":"RCA_CALL_START","call_id":"abc","audio_transport":"audiosocket"}`,
+		`[warning  ] unterminated quote reason='{"message":"oops`,
+		"",
+	}
+	for _, s := range seeds {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, line string) {
+		ParseStructured(line)
+	})
+}
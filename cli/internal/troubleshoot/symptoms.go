@@ -3,6 +3,8 @@ package troubleshoot
 import (
 	"fmt"
 	"strings"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/troubleshoot/rules"
 )
 
 // SymptomChecker performs symptom-specific analysis
@@ -29,6 +31,14 @@ func (sc *SymptomChecker) AnalyzeSymptom(analysis *Analysis, logData string) {
 	case "one-way":
 		sc.analyzeOneWay(analysis, logData)
 	}
+
+	// Additive data-driven pass: default + site-specific rules (see
+	// cli/internal/troubleshoot/rules) layered on top of the hardcoded
+	// checks above. Best-effort — a missing/invalid rules.d file shouldn't
+	// break symptom analysis.
+	if rs, err := rules.LoadMerged(); err == nil {
+		ApplyRules(analysis, logData, rs)
+	}
 }
 
 // analyzeNoAudio checks for complete audio failure
@@ -44,6 +54,15 @@ func (sc *SymptomChecker) analyzeNoAudio(analysis *Analysis, logData string) {
 	lower := strings.ToLower(logData)
 	transport := strings.ToLower(strings.TrimSpace(analysis.AudioTransport))
 
+	// A --self-test run is concrete evidence, not inference from logs: lead
+	// with it when available.
+	if st := analysis.SelfTestMetrics; st != nil && st.RTPPacketsReceived == 0 {
+		analysis.SymptomAnalysis.Findings = append(analysis.SymptomAnalysis.Findings,
+			fmt.Sprintf("❌ Self-test: %s received 0 RTP packets back", st.Transport))
+		analysis.SymptomAnalysis.RootCauses = append(analysis.SymptomAnalysis.RootCauses,
+			"Confirmed by active self-test, not just log absence")
+	}
+
 	if transport == "audiosocket" || transport == "" {
 		// Check AudioSocket connection
 		if !strings.Contains(lower, "\"audiosocket_channel_id\"") && !strings.Contains(lower, "audiosocket channel") {
@@ -110,9 +129,22 @@ func (sc *SymptomChecker) analyzeGarbled(analysis *Analysis, logData string) {
 	lower := strings.ToLower(logData)
 	transport := strings.ToLower(strings.TrimSpace(analysis.AudioTransport))
 
-	// Check for underflows
-	if strings.Contains(lower, "underflow") {
-		count := strings.Count(lower, "underflow")
+	if st := analysis.SelfTestMetrics; st != nil && st.SampleRateDriftPct != 0 {
+		analysis.SymptomAnalysis.Findings = append(analysis.SymptomAnalysis.Findings,
+			fmt.Sprintf("Self-test: measured sample-rate drift %.2f%%", st.SampleRateDriftPct))
+		analysis.SymptomAnalysis.RootCauses = append(analysis.SymptomAnalysis.RootCauses,
+			"Confirmed by active self-test, not just log inference")
+	}
+
+	// Check for underflows. Prefer a typed event count (jitter.underflow
+	// events parsed via ParseLogEvents) over the raw substring count, since
+	// a structured log can mention "underflow" in an unrelated field
+	// (e.g. a benign config dump) without it being a real occurrence.
+	count := ParseLogEvents(logData).ForCallID(analysis.CallID).CountEventContains("underflow")
+	if count == 0 {
+		count = strings.Count(lower, "underflow") // fallback for plain-text logs
+	}
+	if count > 0 {
 		analysis.SymptomAnalysis.Findings = append(analysis.SymptomAnalysis.Findings,
 			fmt.Sprintf("❌ Jitter buffer underflows detected (%d occurrences)", count))
 		analysis.SymptomAnalysis.RootCauses = append(analysis.SymptomAnalysis.RootCauses,
@@ -185,14 +217,30 @@ func (sc *SymptomChecker) analyzeEcho(analysis *Analysis, logData string) {
 			"Check confidence_threshold (try 0.6 or higher)")
 	}
 
-	// Check for audio gate issues
-	if strings.Contains(lower, "gate") || strings.Contains(lower, "gating") {
-		analysis.SymptomAnalysis.Findings = append(analysis.SymptomAnalysis.Findings,
-			"⚠️  Audio gating activity detected")
+	// Check for audio gate issues. gateClosures counts actual gate-related
+	// log events for this call rather than a blob-wide substring hit, so the
+	// 50+ threshold below is evidence, not a guess.
+	callEvents := ParseLogEvents(logData).ForCallID(analysis.CallID)
+	gateClosures := callEvents.CountEventContains("gate") + callEvents.CountEventContains("gating")
+	if gateClosures == 0 && (strings.Contains(lower, "gate") || strings.Contains(lower, "gating")) {
+		gateClosures = -1 // unstructured log mentions gating, but we can't count occurrences
+	}
+	if gateClosures != 0 {
+		if gateClosures > 0 {
+			analysis.SymptomAnalysis.Findings = append(analysis.SymptomAnalysis.Findings,
+				fmt.Sprintf("⚠️  Audio gating activity detected (%d events)", gateClosures))
+		} else {
+			analysis.SymptomAnalysis.Findings = append(analysis.SymptomAnalysis.Findings,
+				"⚠️  Audio gating activity detected")
+		}
 		analysis.SymptomAnalysis.RootCauses = append(analysis.SymptomAnalysis.RootCauses,
 			"Audio gate may be opening/closing rapidly")
 		analysis.SymptomAnalysis.Actions = append(analysis.SymptomAnalysis.Actions,
 			"Check post_tts_end_protection_ms setting")
+		if gateClosures >= 50 {
+			analysis.SymptomAnalysis.Findings = append(analysis.SymptomAnalysis.Findings,
+				fmt.Sprintf("❌ Gate flutter: %d closures is at or above the 50-closure threshold", gateClosures))
+		}
 		analysis.SymptomAnalysis.Actions = append(analysis.SymptomAnalysis.Actions,
 			"Verify gate isn't fluttering (50+ closures = issue)")
 	}
@@ -272,6 +320,13 @@ func (sc *SymptomChecker) analyzeOneWay(analysis *Analysis, logData string) {
 
 	lower := strings.ToLower(logData)
 
+	if st := analysis.SelfTestMetrics; st != nil && st.InjectedPhrase != "" && !st.STTMatched {
+		analysis.SymptomAnalysis.Findings = append(analysis.SymptomAnalysis.Findings,
+			fmt.Sprintf("❌ Self-test: STT returned %q for injected phrase %q", st.STTTranscript, st.InjectedPhrase))
+		analysis.SymptomAnalysis.RootCauses = append(analysis.SymptomAnalysis.RootCauses,
+			"Confirmed by active self-test: caller → agent audio path is not producing a usable transcript")
+	}
+
 	// Check transcription (caller → agent)
 	hasTranscription := strings.Contains(lower, "transcription") || strings.Contains(lower, "transcript")
 	if !hasTranscription {
@@ -0,0 +1,132 @@
+package troubleshoot
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// sparkBlocks are the 8 Unicode block elements (U+2581..U+2588), used by
+// sparkline to render a compact inline bar chart.
+var sparkBlocks = []rune{'▁', '▂', '▃', '▄', '▅', '▆', '▇', '█'}
+
+// sparkline renders values as a single-line bar chart, scaled so the
+// largest |value| maps to a full block. Returns "" if there's nothing
+// non-zero to draw.
+func sparkline(values []float64) string {
+	max := 0.0
+	for _, v := range values {
+		if abs(v) > max {
+			max = abs(v)
+		}
+	}
+	if max == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		idx := int(abs(v) / max * float64(len(sparkBlocks)-1))
+		if idx < 0 {
+			idx = 0
+		} else if idx >= len(sparkBlocks) {
+			idx = len(sparkBlocks) - 1
+		}
+		b.WriteRune(sparkBlocks[idx])
+	}
+	return b.String()
+}
+
+// timeseriesRow is one row of the --timeseries-out CSV dump.
+type timeseriesRow struct {
+	SegmentIndex int
+	StartTs      float64
+	DurationMs   float64
+	BytesSent    int
+	DriftPct     float64
+	Underflows   int
+	GateClosures int
+}
+
+// buildTimeseriesRows walks metrics.StreamingSummaries (skipping greeting
+// segments) into per-segment rows for --timeseries-out and the sparklines
+// in displayMetrics. ai_engine's logs don't attribute underflows or gate
+// closures to individual segments, only to the call as a whole, so those
+// two columns distribute the call-level totals proportionally to each
+// segment's share of total frames rather than repeat the call total on
+// every row — a documented approximation, not a measured per-segment count.
+func buildTimeseriesRows(metrics *CallMetrics) []timeseriesRow {
+	if metrics == nil || len(metrics.StreamingSummaries) == 0 {
+		return nil
+	}
+
+	type segFrames struct {
+		idx    int
+		s      StreamingSummary
+		frames int
+	}
+	var segs []segFrames
+	totalFrames := 0
+	for i, s := range metrics.StreamingSummaries {
+		if s.IsGreeting {
+			continue
+		}
+		frames := s.BytesSent / 320
+		segs = append(segs, segFrames{idx: i, s: s, frames: frames})
+		totalFrames += frames
+	}
+
+	var rows []timeseriesRow
+	offset := 0.0
+	for _, sg := range segs {
+		dur := sg.s.WallSeconds
+		if dur <= 0 {
+			dur = sg.s.EffectiveSeconds
+		}
+
+		var underflows, gateClosures int
+		if totalFrames > 0 {
+			share := float64(sg.frames) / float64(totalFrames)
+			underflows = int(share*float64(metrics.UnderflowCount) + 0.5)
+			gateClosures = int(share*float64(metrics.GateClosures) + 0.5)
+		}
+
+		rows = append(rows, timeseriesRow{
+			SegmentIndex: sg.idx,
+			StartTs:      offset,
+			DurationMs:   dur * 1000,
+			BytesSent:    sg.s.BytesSent,
+			DriftPct:     sg.s.DriftPct,
+			Underflows:   underflows,
+			GateClosures: gateClosures,
+		})
+		offset += dur
+	}
+	return rows
+}
+
+// writeTimeseriesCSV writes buildTimeseriesRows(metrics) to path as CSV, one
+// row per non-greeting segment (segment_index,start_ts,duration_ms,
+// bytes_sent,drift_pct,underflows,gate_closures), so operators can plot
+// drift/underflow trends across many calls instead of only seeing the
+// single worst-case number agent rca prints.
+func writeTimeseriesCSV(path string, metrics *CallMetrics) error {
+	rows := buildTimeseriesRows(metrics)
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create timeseries output file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := fmt.Fprintln(f, "segment_index,start_ts,duration_ms,bytes_sent,drift_pct,underflows,gate_closures"); err != nil {
+		return err
+	}
+	for _, r := range rows {
+		if _, err := fmt.Fprintf(f, "%d,%.3f,%.1f,%d,%.2f,%d,%d\n",
+			r.SegmentIndex, r.StartTs, r.DurationMs, r.BytesSent, r.DriftPct, r.Underflows, r.GateClosures); err != nil {
+			return err
+		}
+	}
+	return nil
+}
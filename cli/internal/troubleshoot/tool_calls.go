@@ -3,6 +3,7 @@ package troubleshoot
 import (
 	"regexp"
 	"strings"
+	"time"
 )
 
 type ToolCallRecord struct {
@@ -10,6 +11,13 @@ type ToolCallRecord struct {
 	Status    string `json:"status,omitempty"`
 	Message   string `json:"message,omitempty"`
 	Arguments string `json:"arguments,omitempty"`
+	Time      string `json:"time,omitempty"` // the log line's leading timestamp, if any
+
+	// Extras holds whichever fields ExtractOptions.Mask selected, projected
+	// from the line's flattened fields (plus Arguments decoded and
+	// flattened under "arguments."). Left nil unless a mask is given, so
+	// existing callers see no change in output.
+	Extras map[string]string `json:"extras,omitempty"`
 }
 
 var (
@@ -17,16 +25,72 @@ var (
 	toolExecRe = regexp.MustCompile(`(?i)Tool\s+([a-zA-Z0-9_]+)\s+executed:\s*([a-zA-Z0-9_]+)`)
 )
 
-// ExtractToolCalls parses log data to extract tool call invocations and results.
+// ExtractOptions bounds and projects what an extractor (ExtractToolCalls
+// and any future sibling) returns, so a caller that only wants a handful
+// of fields from a few recent calls doesn't pay for or carry around
+// everything.
+type ExtractOptions struct {
+	// Mask is a comma-separated list of dotted field paths (see
+	// CompileFieldMask) controlling which fields land in a record's
+	// Extras. Empty means "don't populate Extras at all".
+	Mask string
+	// MaxResults caps the number of records returned; 0 means unlimited.
+	MaxResults int
+	// Since/Until bound records by their line's timestamp (parsed via
+	// ParseStructured's leading-RFC3339 detection); zero value means
+	// unbounded. A record whose line has no parseable timestamp is never
+	// excluded by these, since there's nothing to compare.
+	Since, Until time.Time
+	// Redact, if set, scrubs a record's Arguments/Message/Extras before
+	// they're returned (see Redactor). Nil means no redaction, the same as
+	// today's behavior.
+	Redact *Redactor
+}
+
+// ExtractToolCalls parses log data to extract tool call invocations and
+// results, with no field mask or limits applied. It uses ParseStructured
+// (the same tokenizer LogEvent-based symptom analysis uses) so
+// function_call_id correlation works whether the line is JSON-per-line,
+// logfmt, or console key=value.
 func ExtractToolCalls(logData string) []ToolCallRecord {
-	lines := strings.Split(logData, "\n")
+	return ExtractToolCallsWithOptions(logData, ExtractOptions{})
+}
+
+// ExtractToolCallsWithOptions is ExtractToolCalls plus opts' field-mask
+// projection, result cap, time-range filter, and (if opts.Redact is set)
+// PII/secret redaction of Arguments, Message, and Extras before a record
+// is returned. It runs on top of Parser (via strings.NewReader) so the
+// line-at-a-time correlation logic lives in one place regardless of
+// whether the caller has a whole log as a string or is streaming it
+// through a Parser.
+func ExtractToolCallsWithOptions(logData string, opts ExtractOptions) []ToolCallRecord {
+	p := NewParser(strings.NewReader(logData))
+	return collectToolCalls(p.next, opts)
+}
+
+// collectToolCalls is the shared tool-call correlation loop: it pulls
+// entries from nextEntry (Parser.next, so both the string-based
+// extractors and Parser.CollectToolCalls share one implementation) until
+// nextEntry reports ok=false, applying opts' mask/limit/time-range the
+// same way in both cases.
+func collectToolCalls(nextEntry func() (Entry, bool), opts ExtractOptions) []ToolCallRecord {
+	mask := CompileFieldMask(opts.Mask)
+
 	records := make([]ToolCallRecord, 0, 8)
 	pendingByID := make(map[string]int)
 	pendingByName := make(map[string][]int)
 
-	for _, line := range lines {
-		_, event, fields, ok := parseLogLine(line)
+	for {
+		if opts.MaxResults > 0 && len(records) >= opts.MaxResults {
+			break
+		}
+
+		entry, ok := nextEntry()
 		if !ok {
+			break
+		}
+		ts, event, fields := entry.Time, entry.Event, entry.Fields
+		if !withinTimeRange(ts, opts) {
 			continue
 		}
 
@@ -36,6 +100,16 @@ func ExtractToolCalls(logData string) []ToolCallRecord {
 			rec := ToolCallRecord{
 				Name:      name,
 				Arguments: args,
+				Time:      ts,
+			}
+			if mask != nil {
+				rec.Extras = projectFields(fields, args, mask)
+			}
+			if opts.Redact != nil {
+				rec.Arguments = opts.Redact.redactValue(rec.Arguments)
+				if rec.Extras != nil {
+					_, rec.Extras = opts.Redact.Redact("", rec.Extras)
+				}
 			}
 			records = append(records, rec)
 			idx := len(records) - 1
@@ -69,7 +143,7 @@ func ExtractToolCalls(logData string) []ToolCallRecord {
 				}
 			}
 			if idx == -1 {
-				records = append(records, ToolCallRecord{Name: name})
+				records = append(records, ToolCallRecord{Name: name, Time: ts})
 				idx = len(records) - 1
 			}
 			rec := records[idx]
@@ -77,9 +151,79 @@ func ExtractToolCalls(logData string) []ToolCallRecord {
 			if msg := strings.TrimSpace(fields["message"]); msg != "" {
 				rec.Message = msg
 			}
+			if mask != nil {
+				for k, v := range projectFields(fields, "", mask) {
+					if rec.Extras == nil {
+						rec.Extras = make(map[string]string)
+					}
+					rec.Extras[k] = v
+				}
+			}
+			if opts.Redact != nil {
+				rec.Message = opts.Redact.redactValue(rec.Message)
+				if rec.Extras != nil {
+					_, rec.Extras = opts.Redact.Redact("", rec.Extras)
+				}
+			}
 			records[idx] = rec
 		}
 	}
 
+	if opts.MaxResults > 0 && len(records) > opts.MaxResults {
+		records = records[:opts.MaxResults]
+	}
 	return records
 }
+
+func withinTimeRange(ts string, opts ExtractOptions) bool {
+	if opts.Since.IsZero() && opts.Until.IsZero() {
+		return true
+	}
+	t, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return true // can't tell, don't exclude on an unparseable timestamp
+	}
+	if !opts.Since.IsZero() && t.Before(opts.Since) {
+		return false
+	}
+	if !opts.Until.IsZero() && t.After(opts.Until) {
+		return false
+	}
+	return true
+}
+
+// projectFields builds the candidate field set for a tool-call line
+// (its flattened fields, plus rawArguments decoded and flattened under
+// "arguments." if it parses as a Python-dict-style or JSON object) and
+// returns only the entries mask selects.
+func projectFields(fields map[string]string, rawArguments string, mask *FieldMask) map[string]string {
+	candidate := make(map[string]string, len(fields)+4)
+	for k, v := range fields {
+		candidate[k] = v
+	}
+	if rawArguments != "" {
+		if decoded, ok := FlattenJSON("arguments", pythonDictToJSON(rawArguments), FlattenOptions{}); ok {
+			for k, v := range decoded {
+				candidate[k] = v
+			}
+		}
+	}
+
+	out := make(map[string]string, len(candidate))
+	for k, v := range candidate {
+		if mask.Matches(k) {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// pythonDictToJSON is a best-effort normalizer for the single-quoted
+// Python-repr dict blobs adapters like the Deepgram tool bridge log
+// verbatim (e.g. "{'extension': '2765'}"), turning them into valid JSON
+// by swapping quote characters. It's deliberately simple - it doesn't
+// handle an escaped or embedded apostrophe inside a value - since the
+// adapters this targets only ever log simple flat string/number dicts.
+func pythonDictToJSON(s string) string {
+	return strings.ReplaceAll(s, "'", "\"")
+}
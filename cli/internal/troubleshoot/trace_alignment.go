@@ -0,0 +1,75 @@
+package troubleshoot
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+)
+
+// traceSummaryLinePattern matches the RCA_TRACE_SUMMARY log line ai_engine
+// is expected to emit once per call, right after calling
+// nbtrace.DrainTrace(callID) at hangup. RCA itself never talks to the
+// nbtrace ring buffers directly — like every other config/runtime value
+// here, it only ever sees logs (see the doc comment on
+// AnalyzeFormatAlignment).
+var traceSummaryLinePattern = regexp.MustCompile(`RCA_TRACE_SUMMARY\s+(\{.*\})`)
+
+// traceSummaryLogLine is the JSON shape of a RCA_TRACE_SUMMARY line: the
+// handful of nbtrace.TraceSummary fields RCA can actually act on.
+type traceSummaryLogLine struct {
+	CallID             string  `json:"call_id"`
+	ObservedFrameHz    float64 `json:"observed_frame_hz"`
+	CaptureJitterP95Ms float64 `json:"capture_jitter_p95_ms"`
+	ProviderRTTP95Ms   float64 `json:"provider_rtt_p95_ms"`
+	VADToBargeInP95Ms  float64 `json:"vad_to_bargein_p95_ms"`
+	OverrunsDetected   int     `json:"overruns_detected"`
+}
+
+// extractTraceSummary looks for a RCA_TRACE_SUMMARY log line and returns
+// the parsed summary, or nil if this call's logs don't have per-frame
+// tracing enabled (the common case until ai_engine adopts nbtrace).
+func extractTraceSummary(logData string) *traceSummaryLogLine {
+	m := traceSummaryLinePattern.FindStringSubmatch(logData)
+	if m == nil {
+		return nil
+	}
+	var line traceSummaryLogLine
+	if err := json.Unmarshal([]byte(m[1]), &line); err != nil {
+		return nil
+	}
+	return &line
+}
+
+// ptimeDriftTolerancePct is how far the ring-derived observed frame rate
+// may diverge from the configured ptime's implied rate before it's flagged
+// — wider than the 10% frame-size tolerance in detectMisalignments, since
+// per-frame timestamp jitter is noisier than a byte-count comparison.
+const ptimeDriftTolerancePct = 15.0
+
+// crossCheckTraceCadence compares nbtrace's ring-derived observed frame
+// rate against the rate implied by the assumed defaultPtimeMs (20ms)
+// packetization, and appends an issue if they disagree by more than
+// ptimeDriftTolerancePct. This catches ptime drift that the byte-count-only
+// ObservedFrameSize estimate in analyzeFrameSizes (ProviderBytes/10, a
+// rough guess) can't: a steady stream of correctly-sized frames arriving
+// at the wrong cadence looks fine to that estimate.
+func crossCheckTraceCadence(alignment *FormatAlignment, trace *traceSummaryLogLine) {
+	if trace == nil || trace.ObservedFrameHz <= 0 {
+		return
+	}
+	alignment.TraceObservedFrameHz = trace.ObservedFrameHz
+
+	expectedHz := 1000.0 / float64(defaultPtimeMs)
+	diff := expectedHz - trace.ObservedFrameHz
+	if diff < 0 {
+		diff = -diff
+	}
+	if diff/expectedHz*100 <= ptimeDriftTolerancePct {
+		return
+	}
+
+	alignment.PtimeDriftDetected = true
+	alignment.Issues = append(alignment.Issues, fmt.Sprintf(
+		"ptime drift: expected ~%.1f frames/sec (%dms ptime), nbtrace observed ~%.1f frames/sec",
+		expectedHz, defaultPtimeMs, trace.ObservedFrameHz))
+}
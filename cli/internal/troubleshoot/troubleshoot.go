@@ -4,14 +4,16 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
-	"os/exec"
 	"regexp"
 	"sort"
 	"strings"
 	"time"
 
 	"github.com/fatih/color"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/troubleshoot/capture"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/troubleshoot/selftest"
 )
 
 var (
@@ -42,10 +44,22 @@ type Runner struct {
 	forceLLM    bool
 	list        bool
 	jsonOutput  bool
+	follow      bool
+	followOpts  FollowOptions
+	logSource   LogSource
+	storePath   string
+	outputFormat string
+	lastReport   *RCAReport
+	timeseriesOut string
+	diffBaselineName string
+	writeBaselinePath string
+	bundlePath string
+	explain bool
+	selfTestMode string
 }
 
 // NewRunner creates a new troubleshoot runner
-func NewRunner(callID, symptom string, interactive, collectOnly, noLLM, forceLLM, list, jsonOutput, verbose bool) *Runner {
+func NewRunner(callID, symptom string, interactive, collectOnly, noLLM, forceLLM, list, jsonOutput, follow, verbose bool) *Runner {
 	return &Runner{
 		verbose:     verbose,
 		ctx:         context.Background(),
@@ -57,9 +71,98 @@ func NewRunner(callID, symptom string, interactive, collectOnly, noLLM, forceLLM
 		forceLLM:    forceLLM,
 		list:        list,
 		jsonOutput:  jsonOutput,
+		follow:      follow,
 	}
 }
 
+// SetOutputFormat selects --format=json|junit|text for the single-call `agent
+// rca` report. An empty value falls back to the jsonOutput bool passed to
+// NewRunner (for backward compatibility with the older --json flag).
+func (r *Runner) SetOutputFormat(format string) {
+	r.outputFormat = format
+}
+
+// SetExplain enables --explain: LLM findings are anonymized (SIP URIs, phone
+// numbers, IPs, API keys, tool-call arguments) before they're sent, and
+// responses are cached by a hash of the sanitized input. See Explainer.
+func (r *Runner) SetExplain(explain bool) {
+	r.explain = explain
+}
+
+// SetSelfTest enables --self-test=quick|full: before symptom analysis runs,
+// actively exercise the audio path (see selftest.Run) and feed the resulting
+// Metrics into Analysis so analyzeNoAudio/analyzeGarbled/analyzeOneWay can
+// cite concrete evidence instead of inferring from logs alone. An empty
+// mode disables self-test (the default).
+func (r *Runner) SetSelfTest(mode string) {
+	r.selfTestMode = mode
+}
+
+// LastReport returns the RCAReport built by the most recent Run(), or nil if
+// Run() hasn't produced one yet (e.g. it returned early on "no calls found").
+// Used by `agent rca` to evaluate --fail-under after Run() returns.
+func (r *Runner) LastReport() *RCAReport {
+	return r.lastReport
+}
+
+// SetTimeseriesOut configures --timeseries-out: a CSV path to dump the
+// per-segment drift/underflow timeline to (see writeTimeseriesCSV).
+func (r *Runner) SetTimeseriesOut(path string) {
+	r.timeseriesOut = path
+}
+
+// SetDiffBaseline configures --diff-baseline=<name>: compare this call's
+// metrics against baselines/<name>.json (or the built-in default) and
+// report PASS/FAIL per field (see DiffBaseline).
+func (r *Runner) SetDiffBaseline(name string) {
+	r.diffBaselineName = name
+}
+
+// SetWriteBaseline configures --write-baseline=<path>: snapshot this call's
+// metrics into a new golden baseline JSON file at path (see
+// WriteGoldenBaseline).
+func (r *Runner) SetWriteBaseline(path string) {
+	r.writeBaselinePath = path
+}
+
+// SetBundlePath configures --bundle=<path>: export a zip incident bundle
+// for this call (raw log slice, Analysis/CallMetrics JSON, Markdown report,
+// redacted config) at path (see ExportIncidentBundle).
+func (r *Runner) SetBundlePath(path string) {
+	r.bundlePath = path
+}
+
+// SetFollowOptions configures the filters/output-file used by RunFollow.
+// Kept as a setter rather than more NewRunner parameters since these are
+// only meaningful in --follow mode and are rarely all set at once.
+func (r *Runner) SetFollowOptions(opts FollowOptions) {
+	r.followOpts = opts.withDefaults()
+}
+
+// SetLogSource overrides where ai_engine log lines are read from (default:
+// Docker). Used by `agent rca --log-source` / RCA_LOG_SOURCE.
+func (r *Runner) SetLogSource(src LogSource) {
+	r.logSource = src
+}
+
+// logSourceOrDefault returns the configured LogSource, falling back to
+// NewLogSourceFromEnv("") (Docker, unless RCA_LOG_SOURCE says otherwise) so
+// callers that never call SetLogSource keep working unchanged.
+func (r *Runner) logSourceOrDefault() LogSource {
+	if r.logSource != nil {
+		return r.logSource
+	}
+	return NewLogSourceFromEnv("")
+}
+
+// SetStore enables persisting every generated RCAReport to a local SQLite
+// database at path (see --save/RCA_STORE_PATH and `agent rca
+// history`/`agent rca query`). An empty path disables persistence, which is
+// the default — RCA reports aren't written anywhere unless asked to.
+func (r *Runner) SetStore(path string) {
+	r.storePath = path
+}
+
 // Run executes troubleshooting workflow
 func (r *Runner) Run() error {
 	// Load .env file for API keys
@@ -70,6 +173,11 @@ func (r *Runner) Run() error {
 		return r.listCalls()
 	}
 
+	// Live streaming mode
+	if r.follow {
+		return r.RunFollow()
+	}
+
 	// Determine which call to analyze
 	if r.callID == "" || r.callID == "last" {
 		calls, err := r.getRecentCalls(10)
@@ -157,14 +265,35 @@ func (r *Runner) Run() error {
 		analysis.AudioTransport = strings.ToLower(strings.TrimSpace(header.AudioTransport))
 	}
 
+	if r.selfTestMode != "" {
+		transport := analysis.AudioTransport
+		if transport == "" {
+			transport = "audiosocket"
+		}
+		phrase := ""
+		if r.selfTestMode == string(selftest.ModeFull) {
+			phrase = "the quick brown fox"
+		}
+		if metrics, err := selftest.Run(r.ctx, transport, selftest.Options{Mode: selftest.Mode(r.selfTestMode), Phrase: phrase}); err != nil {
+			if r.verbose {
+				fmt.Fprintf(os.Stderr, "[DEBUG] --self-test failed: %v\n", err)
+			}
+		} else {
+			analysis.SelfTestMetrics = metrics
+		}
+	}
+
 	// Extract structured metrics
 	metrics := ExtractMetrics(logData)
 	analysis.Metrics = metrics
 
 	// Analyze format/sampling alignment
-	formatAlignment := AnalyzeFormatAlignment(metrics, header)
+	formatAlignment := AnalyzeFormatAlignment(metrics, header, logData)
 	metrics.FormatAlignment = formatAlignment
 
+	// Optional: loudness/level analysis, when a raw PCM dump was captured
+	AnalyzeLoudness(metrics, logData, analysis.ProviderRuntime)
+
 	// Compare to golden baselines
 	baselineName := detectBaseline(logData)
 	if baselineName != "" {
@@ -175,6 +304,34 @@ func (r *Runner) Run() error {
 		}
 	}
 
+	// Snapshot a golden baseline from this call, if requested
+	if r.writeBaselinePath != "" {
+		name := baselineName
+		if name == "" {
+			name = "custom"
+		}
+		if err := WriteGoldenBaseline(r.writeBaselinePath, name, metrics); err != nil {
+			if r.verbose {
+				fmt.Fprintf(os.Stderr, "[DEBUG] failed to write --write-baseline: %v\n", err)
+			}
+		} else if !r.jsonOutput && r.outputFormat != "junit" {
+			successColor.Printf("✅ Wrote baseline snapshot (%s) to %s\n\n", name, r.writeBaselinePath)
+		}
+	}
+
+	// Diff against a golden baseline, if requested
+	if r.diffBaselineName != "" {
+		gb, err := LoadGoldenBaseline(r.diffBaselineName)
+		if err != nil {
+			if r.verbose {
+				fmt.Fprintf(os.Stderr, "[DEBUG] failed to load --diff-baseline %q: %v\n", r.diffBaselineName, err)
+			}
+		} else {
+			analysis.GoldenBaselineName = gb.Name
+			analysis.GoldenBaselineChecks = DiffBaseline(metrics, gb)
+		}
+	}
+
 	// Apply symptom-specific analysis
 	if r.symptom != "" {
 		checker := NewSymptomChecker(r.symptom)
@@ -188,19 +345,54 @@ func (r *Runner) Run() error {
 		runLLM = r.forceLLM || shouldRunLLM(analysis, metrics, logData)
 	}
 	if runLLM {
-		llmAnalyzer, err := NewLLMAnalyzer()
+		analyzer, err := NewAnalyzer()
 		if err != nil {
 			// best-effort; do not fail the report
+		} else if r.explain {
+			llmDiagnosis, err = NewExplainer(analyzer).Explain(context.Background(), analysis, logData)
+			_ = err // best-effort; do not fail the report
 		} else {
-			llmDiagnosis, err = llmAnalyzer.AnalyzeWithLLM(analysis, logData)
+			llmDiagnosis, err = analyzer.Analyze(context.Background(), analysis, logData)
 			if err != nil {
 				// best-effort; do not fail the report
 			}
 		}
 	}
 
+	rep := buildRCAReport(analysis, llmDiagnosis)
+	r.lastReport = rep
+	r.saveReport(rep, logData)
+
+	if r.timeseriesOut != "" && analysis.Metrics != nil {
+		if err := writeTimeseriesCSV(r.timeseriesOut, analysis.Metrics); err != nil && r.verbose {
+			fmt.Fprintf(os.Stderr, "[DEBUG] failed to write --timeseries-out: %v\n", err)
+		}
+	}
+
+	if r.bundlePath != "" {
+		if sum, err := r.ExportIncidentBundle(analysis, llmDiagnosis, logData, r.bundlePath); err != nil {
+			if r.verbose {
+				fmt.Fprintf(os.Stderr, "[DEBUG] failed to write --bundle: %v\n", err)
+			}
+		} else if r.outputFormat != "json" && r.outputFormat != "junit" && !r.jsonOutput {
+			successColor.Printf("✅ Wrote incident bundle to %s (sha256: %s)\n\n", r.bundlePath, sum)
+		}
+	}
+
+	switch r.outputFormat {
+	case "json":
+		return r.outputJSON(rep)
+	case "junit":
+		out, err := RenderJUnit(rep)
+		if err != nil {
+			return fmt.Errorf("failed to render junit report: %w", err)
+		}
+		fmt.Println(out)
+		return nil
+	}
+
 	if r.jsonOutput {
-		return r.outputJSON(buildRCAReport(analysis, llmDiagnosis))
+		return r.outputJSON(rep)
 	}
 
 	// Human-readable output
@@ -239,9 +431,17 @@ func (r *Runner) Run() error {
 		r.displayCallQuality(analysis)
 	}
 
+	if analysis.GoldenBaselineChecks != nil {
+		r.displayBaselineDiff(analysis.GoldenBaselineName, analysis.GoldenBaselineChecks)
+	}
+
 	// Show LLM diagnosis
 	if llmDiagnosis != nil {
-		r.displayLLMDiagnosis(llmDiagnosis)
+		if r.explain {
+			r.displayExplanation(llmDiagnosis)
+		} else {
+			r.displayLLMDiagnosis(llmDiagnosis)
+		}
 	}
 
 	// Interactive follow-up
@@ -279,10 +479,32 @@ type RCAReport struct {
 	Metrics            *CallMetrics        `json:"metrics,omitempty"`
 	BaselineComparison *BaselineComparison `json:"baseline_comparison,omitempty"`
 	LLMDiagnosis       *LLMDiagnosis       `json:"llm_diagnosis,omitempty"`
+
+	// Ground-truth packet metrics from --follow --capture, when a pcap was
+	// recorded for this call. See CrossCheckCapture in capture_crosscheck.go for how
+	// these get compared against the log-derived Metrics above.
+	PacketCaptureMetrics *capture.PacketCaptureMetrics `json:"packet_capture_metrics,omitempty"`
+
+	// SchemaVersion identifies the shape of this report for --format=json/junit
+	// consumers (CI pipelines, dashboards). Bump it if fields are removed or
+	// change meaning; adding new omitempty fields does not require a bump.
+	SchemaVersion string `json:"schema_version,omitempty"`
+
+	// QualityScore/QualityIssues mirror what displayCallQuality prints,
+	// via computeCallQuality, so --format=json/junit stays in sync with the
+	// human-readable verdict. Only meaningful when Metrics has evidence
+	// (see metricsHasEvidence); otherwise left at the zero value.
+	QualityScore  float64  `json:"quality_score,omitempty"`
+	QualityIssues []string `json:"quality_issues,omitempty"`
+
+	// BaselineDiff holds --diff-baseline's PASS/FAIL checks, when requested.
+	BaselineDiffName  string           `json:"baseline_diff_name,omitempty"`
+	BaselineDiffChecks []BaselineCheck `json:"baseline_diff_checks,omitempty"`
 }
 
 func buildRCAReport(analysis *Analysis, llm *LLMDiagnosis) *RCAReport {
 	rep := &RCAReport{
+		SchemaVersion:   "1.0",
 		CallID:       analysis.CallID,
 		Header:       analysis.Header,
 		ProviderRuntime: analysis.ProviderRuntime,
@@ -301,6 +523,12 @@ func buildRCAReport(analysis *Analysis, llm *LLMDiagnosis) *RCAReport {
 	rep.Pipeline.HasPlayback = analysis.HasPlayback
 	rep.SymptomAnalysis = analysis.SymptomAnalysis
 	rep.BaselineComparison = analysis.BaselineComparison
+	if score, issues, ok := computeCallQuality(analysis); ok {
+		rep.QualityScore = score
+		rep.QualityIssues = issues
+	}
+	rep.BaselineDiffName = analysis.GoldenBaselineName
+	rep.BaselineDiffChecks = analysis.GoldenBaselineChecks
 	return rep
 }
 
@@ -344,10 +572,24 @@ func (r *Runner) listCalls() error {
 	return nil
 }
 
-// getRecentCalls extracts recent calls from logs
+// getRecentCalls extracts recent calls from the last 24h of logs.
 func (r *Runner) getRecentCalls(limit int) ([]Call, error) {
-	cmd := exec.Command("docker", "logs", "--since", "24h", "ai_engine")
-	output, err := cmd.CombinedOutput()
+	return r.getRecentCallsSince(limit, "24h")
+}
+
+// getRecentCallsSince is getRecentCalls parameterized by the `docker logs
+// --since` window, so RunStats can look back further than the default 24h.
+func (r *Runner) getRecentCallsSince(limit int, since string) ([]Call, error) {
+	window, err := parseSinceDuration(since)
+	if err != nil {
+		return nil, err
+	}
+	rc, err := r.logSourceOrDefault().RecentLines(r.ctx, window)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read logs: %w", err)
+	}
+	defer rc.Close()
+	output, err := io.ReadAll(rc)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read logs: %w", err)
 	}
@@ -459,8 +701,16 @@ func (r *Runner) collectCallData() (string, error) {
 	if since == "" {
 		since = "72h"
 	}
-	cmd := exec.Command("docker", "logs", "--since", since, "ai_engine")
-	output, err := cmd.CombinedOutput()
+	window, err := parseSinceDuration(since)
+	if err != nil {
+		return "", err
+	}
+	rc, err := r.logSourceOrDefault().RecentLines(r.ctx, window)
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+	output, err := io.ReadAll(rc)
 	if err != nil {
 		return "", err
 	}
@@ -573,6 +823,17 @@ type Analysis struct {
 	HasPlayback        bool
 	Symptom            string
 	SymptomAnalysis    *SymptomAnalysis
+
+	// GoldenBaselineName/GoldenBaselineChecks are populated from
+	// --diff-baseline (see DiffBaseline in golden_baseline.go), distinct
+	// from the BaselineComparison above.
+	GoldenBaselineName   string
+	GoldenBaselineChecks []BaselineCheck
+
+	// SelfTestMetrics is populated only when --self-test actively exercised
+	// the audio path (see selftest.Run); nil means the symptom checkers are
+	// inferring from logs alone, same as before --self-test existed.
+	SelfTestMetrics *selftest.Metrics
 }
 
 // analyzeBasic performs basic log analysis
@@ -1140,6 +1401,21 @@ func (r *Runner) displayMetrics(metrics *CallMetrics) {
 		} else {
 			successColor.Println("  Underflows: 0 ✅ NONE")
 		}
+
+		if rows := buildTimeseriesRows(metrics); len(rows) > 1 {
+			driftVals := make([]float64, len(rows))
+			underflowVals := make([]float64, len(rows))
+			for i, row := range rows {
+				driftVals[i] = row.DriftPct
+				underflowVals[i] = float64(row.Underflows)
+			}
+			if s := sparkline(driftVals); s != "" {
+				fmt.Printf("  Drift sparkline:     %s (%d segments)\n", s, len(rows))
+			}
+			if s := sparkline(underflowVals); s != "" {
+				fmt.Printf("  Underflow sparkline: %s\n", s)
+			}
+		}
 		fmt.Println()
 	}
 
@@ -1213,23 +1489,47 @@ func (r *Runner) displayMetrics(metrics *CallMetrics) {
 	}
 }
 
+// evaluateCallQuality scores overall call quality. When StreamingSummaries
+// are available, the score is the duration-weighted aggregate of each
+// segment's own sub-score (see scoreSegments) rather than a single flat
+// deduction from 100 — a few badly-behaved segments on an otherwise long,
+// stable call now pull the score down proportionally to how much of the
+// call they actually covered, instead of contributing the same flat
+// penalty regardless of how long they lasted. Falls back to a flat 100
+// when there's nothing to segment (e.g. no streaming audio logged).
+//
+// The issue strings are unchanged from the old flat-deduction version so
+// --format=json/junit consumers (see RenderJUnit) keep matching on them.
 func evaluateCallQuality(metrics *CallMetrics) (float64, []string) {
 	issues := []string{}
-	score := 100.0
+
+	var score float64
+	if segs := scoreSegments(metrics); len(segs) > 0 {
+		var totalWeight, weighted float64
+		for _, s := range segs {
+			w := s.DurationSeconds
+			if w <= 0 {
+				w = 1
+			}
+			totalWeight += w
+			weighted += s.Score * w
+		}
+		score = weighted / totalWeight
+	} else {
+		score = 100.0
+	}
 
 	// Check provider bytes ratio
 	if len(metrics.ProviderSegments) > 0 && metrics.ProviderBytesTotal > 0 {
 		actualRatio := float64(metrics.EnqueuedBytesTotal) / float64(metrics.ProviderBytesTotal)
 		if actualRatio < 0.95 || actualRatio > 1.05 {
 			issues = append(issues, "Provider bytes pacing issue")
-			score -= 30.0
 		}
 	}
 
 	// Check drift (excluding greeting segments)
 	if absFloat(metrics.WorstDriftPct) > 10.0 {
 		issues = append(issues, fmt.Sprintf("High drift (%.1f%%)", metrics.WorstDriftPct))
-		score -= 25.0
 	}
 
 	// Check underflows (with rate-based severity)
@@ -1242,26 +1542,23 @@ func evaluateCallQuality(metrics *CallMetrics) (float64, []string) {
 
 		if underflowRate >= 5.0 {
 			issues = append(issues, fmt.Sprintf("%d underflows (%.1f%% rate - significant)", metrics.UnderflowCount, underflowRate))
-			score -= 20.0
 		} else if underflowRate >= 1.0 {
 			issues = append(issues, fmt.Sprintf("%d underflows (%.1f%% rate - minor)", metrics.UnderflowCount, underflowRate))
-			score -= 5.0
 		}
 	}
 
 	// Check gate flutter
 	if metrics.GateFlutterDetected {
 		issues = append(issues, "Gate flutter detected")
-		score -= 20.0
 	}
 
-	// Check VAD issues
+	// Check VAD issues (call-level; not attributable to one segment)
 	if metrics.VADSettings != nil && metrics.VADSettings.WebRTCAggressiveness == 0 {
 		issues = append(issues, "VAD too sensitive")
 		score -= 15.0
 	}
 
-	// Check format alignment issues (CRITICAL)
+	// Check format alignment issues (CRITICAL; call-level)
 	if metrics.FormatAlignment != nil {
 		if metrics.FormatAlignment.AudioSocketMismatch {
 			issues = append(issues, "AudioSocket format mismatch")
@@ -1277,30 +1574,29 @@ func evaluateCallQuality(metrics *CallMetrics) (float64, []string) {
 		}
 	}
 
+	if score < 0 {
+		score = 0
+	} else if score > 100 {
+		score = 100
+	}
+
 	return score, issues
 }
 
-// displayCallQuality shows overall call quality verdict
-func (r *Runner) displayCallQuality(analysis *Analysis) {
-	fmt.Println("═══════════════════════════════════════════")
-	fmt.Println("🎯 OVERALL CALL QUALITY")
-	fmt.Println("═══════════════════════════════════════════")
-	fmt.Println()
-
+// computeCallQuality returns the overall call-quality score and issue list
+// exactly as displayCallQuality renders them. Factored out of
+// displayCallQuality so buildRCAReport's --format=json/junit output (see
+// RCAReport.QualityScore/QualityIssues) can't drift from what a human
+// running `agent rca` actually sees.
+func computeCallQuality(analysis *Analysis) (score float64, issues []string, ok bool) {
 	metrics := analysis.Metrics
 	if !metricsHasEvidence(metrics) {
-		warningColor.Println("Verdict: ⚠️  INSUFFICIENT DATA - No RCA metrics extracted from logs")
-		fmt.Println("Quality Score: N/A")
-		fmt.Println()
-		fmt.Println("Notes:")
-		fmt.Println("  • This usually means ai_engine is running in console/info logging without RCA metric events,")
-		fmt.Println("    or the collected logs do not include the relevant streaming/provider markers for this call.")
-		fmt.Println("  • Enable debug logs for richer RCA, then re-run a test call and run: agent rca")
-		fmt.Println()
-		return
+		return 0, nil, false
 	}
 
-	score, issues := evaluateCallQuality(metrics)
+	metrics.MOS = computeMOS(metrics, analysis.Header)
+
+	score, issues = evaluateCallQuality(metrics)
 
 	// Treat errors as call-stability issues even if audio metrics look good.
 	// (e.g., provider websocket closes, auth failures, ARI failures, etc.)
@@ -1316,6 +1612,29 @@ func (r *Runner) displayCallQuality(analysis *Analysis) {
 		}
 	}
 
+	return score, issues, true
+}
+
+// displayCallQuality shows overall call quality verdict
+func (r *Runner) displayCallQuality(analysis *Analysis) {
+	fmt.Println("═══════════════════════════════════════════")
+	fmt.Println("🎯 OVERALL CALL QUALITY")
+	fmt.Println("═══════════════════════════════════════════")
+	fmt.Println()
+
+	score, issues, ok := computeCallQuality(analysis)
+	if !ok {
+		warningColor.Println("Verdict: ⚠️  INSUFFICIENT DATA - No RCA metrics extracted from logs")
+		fmt.Println("Quality Score: N/A")
+		fmt.Println()
+		fmt.Println("Notes:")
+		fmt.Println("  • This usually means ai_engine is running in console/info logging without RCA metric events,")
+		fmt.Println("    or the collected logs do not include the relevant streaming/provider markers for this call.")
+		fmt.Println("  • Enable debug logs for richer RCA, then re-run a test call and run: agent rca")
+		fmt.Println()
+		return
+	}
+
 	// Determine verdict
 	if score >= 90 {
 		successColor.Println("Verdict: ✅ EXCELLENT - No significant issues detected")
@@ -1328,6 +1647,9 @@ func (r *Runner) displayCallQuality(analysis *Analysis) {
 	}
 
 	fmt.Printf("Quality Score: %.0f/100\n", score)
+	if analysis.Metrics.MOS > 0 {
+		fmt.Printf("Estimated MOS: %.2f/4.5\n", analysis.Metrics.MOS)
+	}
 
 	if len(issues) > 0 {
 		fmt.Println("\nIssues Detected:")
@@ -1342,6 +1664,18 @@ func (r *Runner) displayCallQuality(analysis *Analysis) {
 		fmt.Println("✅ Clean audio expected")
 	}
 
+	if segs := worstSegments(scoreSegments(analysis.Metrics), 3); len(segs) > 0 {
+		fmt.Println("\nWorst Segments:")
+		for _, s := range segs {
+			dominant := s.DominantIssue
+			if dominant == "" {
+				dominant = "none"
+			}
+			fmt.Printf("  • segment %d @ ~%.1fs (%.1fs long): score %.0f/100 - %s\n",
+				s.Index, s.StartOffsetSeconds, s.DurationSeconds, s.Score, dominant)
+		}
+	}
+
 	fmt.Println()
 }
 
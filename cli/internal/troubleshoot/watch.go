@@ -0,0 +1,256 @@
+package troubleshoot
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// WatchOptions configures `agent rca --watch`: a continuous mode that
+// re-analyzes each active call's recent log lines on a timer, instead of
+// waiting for the call to end. It reads the same followLogLines stream as
+// --follow, but FollowOptions' filters and idle timeout are not applied.
+type WatchOptions struct {
+	Window   time.Duration // how much recent history to keep per call (default 5m)
+	Interval time.Duration // how often to re-run the pipeline over the window (default 30s)
+
+	WebhookURL     string  // POST alerts here when a call crosses ScoreThreshold or grows a new non-benign error class
+	ScoreThreshold float64 // alert when evaluateCallQuality's score drops below this (default 70)
+	DryRun         bool    // print what would be POSTed instead of sending it
+
+	BundleDir string // directory to write --bundle-style incident bundles alongside each alert (default os.TempDir())
+
+	Debounce time.Duration // minimum time between two alerts for the same call (default 5m)
+}
+
+func (o WatchOptions) withDefaults() WatchOptions {
+	if o.Window <= 0 {
+		o.Window = 5 * time.Minute
+	}
+	if o.Interval <= 0 {
+		o.Interval = 30 * time.Second
+	}
+	if o.ScoreThreshold <= 0 {
+		o.ScoreThreshold = 70
+	}
+	if o.BundleDir == "" {
+		o.BundleDir = os.TempDir()
+	}
+	if o.Debounce <= 0 {
+		o.Debounce = 5 * time.Minute
+	}
+	return o
+}
+
+// watchLine is one buffered log line plus the wall-clock time it arrived,
+// used to trim the sliding window. Logs don't carry a parseable timestamp
+// common to every source, so arrival time is the best available proxy for
+// "how old is this line" — a documented approximation, not the event time.
+type watchLine struct {
+	text string
+	seen time.Time
+}
+
+// watchCall tracks one active call for --watch: its sliding window of log
+// lines and enough state to debounce repeat alerts and detect newly
+// appearing non-benign error classes.
+type watchCall struct {
+	lines       []watchLine
+	seenClasses map[string]bool
+	lastAlert   time.Time
+}
+
+func newWatchCall() *watchCall {
+	return &watchCall{seenClasses: make(map[string]bool)}
+}
+
+// errorClassPattern strips digits and quoted/bracketed identifiers from an
+// error line so two occurrences of "the same" error (different call IDs,
+// timestamps, ports) collapse to one class for new-error-class detection.
+var errorClassPattern = regexp.MustCompile(`[0-9]+|"[^"]*"|\[[^\]]*\]`)
+
+func errorClass(line string) string {
+	return strings.TrimSpace(errorClassPattern.ReplaceAllString(line, ""))
+}
+
+// watchAlert is the JSON payload POSTed to WatchOptions.WebhookURL (or
+// printed under --dry-run). Generic enough for a Slack incoming webhook
+// (Slack renders unknown top-level fields as a raw JSON block) or a plain
+// JSON collector.
+type watchAlert struct {
+	CallID     string   `json:"call_id"`
+	Score      float64  `json:"quality_score"`
+	Issues     []string `json:"issues"`
+	NewErrors  []string `json:"new_error_classes,omitempty"`
+	BundlePath string   `json:"bundle_path,omitempty"`
+	Text       string   `json:"text"` // Slack-compatible top-level summary
+}
+
+// RunWatch tails ai_engine logs like RunFollow, but instead of waiting for
+// each call to end, it re-runs the analysis pipeline over a trailing
+// WatchOptions.Window of each active call's lines every Interval. When the
+// rolling evaluateCallQuality score drops below ScoreThreshold, or a new
+// non-benign error class (isErrorLine minus isBenignRCAErrorLine) appears,
+// it fires a webhook alert — debounced per call so one bad stretch doesn't
+// flap.
+func (r *Runner) RunWatch(opts WatchOptions) error {
+	LoadEnvFile()
+	opts = opts.withDefaults()
+
+	lines, err := r.followLogLines(r.ctx)
+	if err != nil {
+		return fmt.Errorf("failed to follow logs: %w", err)
+	}
+
+	calls := make(map[string]*watchCall)
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.ctx.Done():
+			return nil
+
+		case line, ok := <-lines:
+			if !ok {
+				return nil
+			}
+			id := extractCallIDFromLine(line)
+			if id == "" {
+				continue
+			}
+			c, ok := calls[id]
+			if !ok {
+				c = newWatchCall()
+				calls[id] = c
+			}
+			c.lines = append(c.lines, watchLine{text: line, seen: time.Now()})
+			if isCallEndLine(line) {
+				delete(calls, id)
+			}
+
+		case <-ticker.C:
+			now := time.Now()
+			for id, c := range calls {
+				c.trim(now, opts.Window)
+				if len(c.lines) == 0 {
+					delete(calls, id)
+					continue
+				}
+				r.evaluateWatchedCall(id, c, opts, now)
+			}
+		}
+	}
+}
+
+// trim drops lines older than window relative to now, keeping the call's
+// buffer bounded to its sliding window instead of growing for the whole
+// call duration.
+func (c *watchCall) trim(now time.Time, window time.Duration) {
+	cutoff := now.Add(-window)
+	i := 0
+	for i < len(c.lines) && c.lines[i].seen.Before(cutoff) {
+		i++
+	}
+	if i > 0 {
+		c.lines = c.lines[i:]
+	}
+}
+
+// evaluateWatchedCall re-runs the analysis pipeline over c's current
+// window and alerts if the score has crossed opts.ScoreThreshold or a new
+// non-benign error class has appeared, subject to opts.Debounce.
+func (r *Runner) evaluateWatchedCall(callID string, c *watchCall, opts WatchOptions, now time.Time) {
+	logLines := make([]string, len(c.lines))
+	for i, l := range c.lines {
+		logLines[i] = l.text
+	}
+	logData := strings.Join(logLines, "\n")
+
+	metrics := ExtractMetrics(logData)
+	score, issues := evaluateCallQuality(metrics)
+
+	var newErrors []string
+	for _, l := range logLines {
+		if !isErrorLine(l) || isBenignRCAErrorLine(l) {
+			continue
+		}
+		class := errorClass(l)
+		if class == "" || c.seenClasses[class] {
+			continue
+		}
+		c.seenClasses[class] = true
+		newErrors = append(newErrors, class)
+	}
+
+	shouldAlert := score < opts.ScoreThreshold || len(newErrors) > 0
+	if !shouldAlert {
+		return
+	}
+	if !c.lastAlert.IsZero() && now.Sub(c.lastAlert) < opts.Debounce {
+		return
+	}
+	c.lastAlert = now
+
+	sub := &Runner{verbose: r.verbose, ctx: r.ctx, callID: callID}
+	analysis := sub.analyzeBasic(logData)
+	analysis.Header = ExtractRCAHeader(logData)
+	analysis.ProviderRuntime = ExtractProviderRuntimeAudio(logData)
+	analysis.Metrics = metrics
+
+	bundlePath := filepath.Join(opts.BundleDir, fmt.Sprintf("rca-watch-%s-%d.zip", sanitizeForFilename(callID), now.Unix()))
+	if _, err := r.ExportIncidentBundle(analysis, nil, logData, bundlePath); err != nil {
+		bundlePath = ""
+		if r.verbose {
+			fmt.Fprintf(os.Stderr, "[DEBUG] --watch: failed to write incident bundle for call %s: %v\n", callID, err)
+		}
+	}
+
+	alert := watchAlert{
+		CallID:     callID,
+		Score:      score,
+		Issues:     issues,
+		NewErrors:  newErrors,
+		BundlePath: bundlePath,
+		Text:       fmt.Sprintf("RCA watch: call %s quality score %.0f/100 (%s)", callID, score, strings.Join(issues, "; ")),
+	}
+	r.sendWatchAlert(alert, opts)
+}
+
+// sanitizeForFilename replaces characters that are awkward in a filename
+// (call IDs are Asterisk channel IDs like "1234567890.42") with "_".
+func sanitizeForFilename(s string) string {
+	return strings.NewReplacer("/", "_", ":", "_", " ", "_").Replace(s)
+}
+
+// sendWatchAlert POSTs alert as JSON to opts.WebhookURL, or just prints it
+// under opts.DryRun.
+func (r *Runner) sendWatchAlert(alert watchAlert, opts WatchOptions) {
+	data, err := json.MarshalIndent(alert, "", "  ")
+	if err != nil {
+		return
+	}
+
+	if opts.DryRun || opts.WebhookURL == "" {
+		fmt.Printf("[--watch dry-run] would POST to %s:\n%s\n", opts.WebhookURL, data)
+		return
+	}
+
+	resp, err := http.Post(opts.WebhookURL, "application/json", bytes.NewReader(data))
+	if err != nil {
+		if r.verbose {
+			fmt.Fprintf(os.Stderr, "[DEBUG] --watch: webhook POST failed for call %s: %v\n", alert.CallID, err)
+		}
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 && r.verbose {
+		fmt.Fprintf(os.Stderr, "[DEBUG] --watch: webhook returned status %s for call %s\n", resp.Status, alert.CallID)
+	}
+}
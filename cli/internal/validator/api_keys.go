@@ -124,7 +124,13 @@ func ValidateGoogleKey(apiKey string) error {
 	return nil
 }
 
-// ValidateAPIKey validates an API key for the given provider
+// ValidateAPIKey validates an API key for the given provider against this
+// package's closed switch of known providers. providers.ValidateAPIKey is
+// the registry-backed replacement (consults the same per-provider functions
+// via each Descriptor); it lives in the providers package rather than here
+// because a provider Descriptor's ValidateAPIKey field points at the
+// functions below, and this package can't import providers back without a
+// cycle.
 func ValidateAPIKey(provider, apiKey string) error {
 	if apiKey == "" {
 		return fmt.Errorf("API key cannot be empty")
@@ -0,0 +1,274 @@
+package validator
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ProbeReport is the structured result of a ProviderProbe: not just "is this
+// key valid" (ValidateAPIKey) but "can this key actually run the pipeline
+// this config asks for" - the specific model, quota headroom, and latency.
+type ProbeReport struct {
+	Provider       string
+	AuthOK         bool
+	ModelRequested string
+	ModelAvailable bool
+	Quota          string // free-form: remaining credit/balance, or "" if the provider doesn't expose one
+	LatencyMs      int64
+	Region         string
+	Error          string // set instead of returning an error, so a cached/stale report can still be inspected
+}
+
+// ProviderProbeFunc actually calls out to a provider to build a ProbeReport.
+type ProviderProbeFunc func(apiKey, model string) (*ProbeReport, error)
+
+var probeRegistry = map[string]ProviderProbeFunc{
+	"openai_realtime": probeOpenAI,
+	"deepgram":        probeDeepgram,
+	"google_live":     probeGoogle,
+}
+
+// Probe runs the named provider's capability probe, through a TTL cache so
+// repeated wizard steps (re-displaying the same "is my key good" screen)
+// don't hammer the provider's API and risk rate-limiting the user mid-setup.
+func Probe(provider, apiKey, model string) (*ProbeReport, error) {
+	fn, ok := probeRegistry[provider]
+	if !ok {
+		return nil, fmt.Errorf("no probe registered for provider: %s", provider)
+	}
+	key := probeCacheKey(provider, apiKey, model)
+	if report, ok := sharedProbeCache.get(key); ok {
+		return report, nil
+	}
+	report, err := fn(apiKey, model)
+	if err != nil {
+		return nil, err
+	}
+	sharedProbeCache.put(key, report)
+	return report, nil
+}
+
+func probeCacheKey(provider, apiKey, model string) string {
+	sum := sha256.Sum256([]byte(provider + "\x00" + apiKey + "\x00" + model))
+	return fmt.Sprintf("%x", sum)
+}
+
+// probeCacheTTL is how long a ProbeReport is reused before re-probing the
+// provider. Short enough that a just-rotated key or newly-granted quota
+// shows up within a setup session, long enough that clicking back-and-forth
+// through wizard steps doesn't re-hit the network every time.
+const probeCacheTTL = 5 * time.Minute
+
+type probeCacheEntry struct {
+	report  *ProbeReport
+	expires time.Time
+}
+
+type probeCache struct {
+	mu    sync.Mutex
+	byKey map[string]probeCacheEntry
+}
+
+func (c *probeCache) get(key string) (*ProbeReport, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.byKey[key]
+	if !ok || time.Now().After(entry.expires) {
+		return nil, false
+	}
+	return entry.report, true
+}
+
+func (c *probeCache) put(key string, report *ProbeReport) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.byKey == nil {
+		c.byKey = map[string]probeCacheEntry{}
+	}
+	c.byKey[key] = probeCacheEntry{report: report, expires: time.Now().Add(probeCacheTTL)}
+}
+
+var sharedProbeCache = &probeCache{}
+
+func probeOpenAI(apiKey, model string) (*ProbeReport, error) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	start := time.Now()
+
+	req, err := http.NewRequest("GET", "https://api.openai.com/v1/models", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("network error: %w (check your internet connection)", err)
+	}
+	defer resp.Body.Close()
+	latency := time.Since(start).Milliseconds()
+
+	report := &ProbeReport{Provider: "openai_realtime", ModelRequested: model, LatencyMs: latency}
+	if resp.StatusCode == 401 {
+		report.Error = "invalid API key (authentication failed)"
+		return report, nil
+	}
+	report.AuthOK = true
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		report.Error = fmt.Sprintf("API returned status %d: %s", resp.StatusCode, string(body))
+		return report, nil
+	}
+
+	var result struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	for _, m := range result.Data {
+		if m.ID == model {
+			report.ModelAvailable = true
+			break
+		}
+	}
+	if model == "" {
+		report.ModelAvailable = len(result.Data) > 0
+	}
+	// OpenAI's /v1/models response doesn't carry a quota/balance field, so
+	// Quota is left blank here (see ProbeReport doc comment).
+	return report, nil
+}
+
+func probeDeepgram(apiKey, model string) (*ProbeReport, error) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	start := time.Now()
+
+	req, err := http.NewRequest("GET", "https://api.deepgram.com/v1/projects", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Token "+apiKey)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("network error: %w (check your internet connection)", err)
+	}
+	defer resp.Body.Close()
+	latency := time.Since(start).Milliseconds()
+
+	report := &ProbeReport{Provider: "deepgram", ModelRequested: model, LatencyMs: latency}
+	if resp.StatusCode == 401 || resp.StatusCode == 403 {
+		report.Error = "invalid API key (authentication failed)"
+		return report, nil
+	}
+	report.AuthOK = true
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		report.Error = fmt.Sprintf("API returned status %d: %s", resp.StatusCode, string(body))
+		return report, nil
+	}
+
+	var result struct {
+		Projects []struct {
+			ProjectID string `json:"project_id"`
+		} `json:"projects"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	if len(result.Projects) == 0 {
+		report.Error = "API key valid but no projects found"
+		return report, nil
+	}
+	// The streaming model itself isn't listed per-project over this
+	// endpoint; nova-2* models are enabled account-wide on current plans,
+	// so we treat having a project as "model available" rather than
+	// guessing at an unstable per-model capability endpoint.
+	report.ModelAvailable = true
+
+	balance, err := fetchDeepgramBalance(client, apiKey, result.Projects[0].ProjectID)
+	if err == nil {
+		report.Quota = balance
+	}
+	return report, nil
+}
+
+func fetchDeepgramBalance(client *http.Client, apiKey, projectID string) (string, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("https://api.deepgram.com/v1/projects/%s/balances", projectID), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Token "+apiKey)
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("balances endpoint returned status %d", resp.StatusCode)
+	}
+	var result struct {
+		Balances []struct {
+			Amount float64 `json:"amount"`
+			Units  string  `json:"units"`
+		} `json:"balances"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", err
+	}
+	if len(result.Balances) == 0 {
+		return "", fmt.Errorf("no balance entries returned")
+	}
+	return fmt.Sprintf("%.2f %s", result.Balances[0].Amount, result.Balances[0].Units), nil
+}
+
+func probeGoogle(apiKey, model string) (*ProbeReport, error) {
+	if len(apiKey) < 30 {
+		return &ProbeReport{Provider: "google_live", ModelRequested: model, Error: "API key appears too short (expected ~39 characters)"}, nil
+	}
+	if model == "" {
+		model = "gemini-2.0-flash-exp"
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	start := time.Now()
+
+	url := fmt.Sprintf("https://generativelanguage.googleapis.com/v1beta/models/%s:generateContent?key=%s", model, apiKey)
+	payload := []byte(`{"contents":[{"parts":[{"text":"ping"}]}],"generationConfig":{"maxOutputTokens":1}}`)
+	req, err := http.NewRequest("POST", url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("network error: %w (check your internet connection)", err)
+	}
+	defer resp.Body.Close()
+	latency := time.Since(start).Milliseconds()
+
+	report := &ProbeReport{Provider: "google_live", ModelRequested: model, LatencyMs: latency}
+	switch resp.StatusCode {
+	case 200:
+		report.AuthOK = true
+		report.ModelAvailable = true
+	case 401, 403:
+		report.Error = "invalid API key (authentication failed)"
+	case 404:
+		report.AuthOK = true
+		report.Error = fmt.Sprintf("model %q not available for this key", model)
+	default:
+		body, _ := io.ReadAll(resp.Body)
+		report.Error = fmt.Sprintf("API returned status %d: %s", resp.StatusCode, string(body))
+	}
+	return report, nil
+}
@@ -26,11 +26,49 @@ type Config struct {
 	ActivePipeline  string
 	DefaultProvider string
 
+	// Pipeline-level audio format profiles, keyed by profile name (e.g.
+	// "openai_realtime", "deepgram"). ActivePipeline selects one of these.
+	Profiles map[string]PipelineProfile
+
 	// File paths
 	EnvPath  string
 	YAMLPath string
 }
 
+// PipelineProfile is the known-good audio format combo for one pipeline.
+// Operators swap providers by selecting a profile instead of hand-editing
+// .env, so a known-good OpenAI Realtime vs. Deepgram combo isn't lost.
+type PipelineProfile struct {
+	SampleRateHz         int    `yaml:"sample_rate_hz"`
+	AudioSocketFormat    string `yaml:"audiosocket_format"`
+	ProviderInputFormat  string `yaml:"provider_input_format"`
+	ProviderOutputFormat string `yaml:"provider_output_format"`
+	Channels             int    `yaml:"channels"`
+	BitDepth             int    `yaml:"bit_depth"`
+}
+
+// defaultProfiles seeds the table when config/ai-agent.local.yaml has no
+// "pipelines" block yet, so existing flat-key deployments keep working.
+func defaultProfiles() map[string]PipelineProfile {
+	return map[string]PipelineProfile{
+		"openai_realtime": {SampleRateHz: 8000, AudioSocketFormat: "slin", ProviderInputFormat: "pcm16", ProviderOutputFormat: "pcm16", Channels: 1, BitDepth: 16},
+		"deepgram":        {SampleRateHz: 8000, AudioSocketFormat: "slin", ProviderInputFormat: "linear16", ProviderOutputFormat: "mulaw", Channels: 1, BitDepth: 16},
+	}
+}
+
+// ActiveProfile returns the profile selected by ActivePipeline, or a zero
+// PipelineProfile and false when the table has no entry for it. No profile
+// is derived from the flat AudioTransport field; pre-profile configs get
+// defaultProfiles' entries from loadYAML instead.
+func (c *Config) ActiveProfile() (PipelineProfile, bool) {
+	if c.Profiles != nil {
+		if p, ok := c.Profiles[c.ActivePipeline]; ok {
+			return p, true
+		}
+	}
+	return PipelineProfile{}, false
+}
+
 // LoadConfig reads current configuration from .env and YAML
 func LoadConfig() (*Config, error) {
 	// Try to find .env - check current dir and parent dir
@@ -172,9 +210,53 @@ func (c *Config) loadYAML() error {
 		c.DefaultProvider = val
 	}
 
+	// Extract per-pipeline audio profiles. Fall back to the built-in
+	// defaults so configs written before this table existed keep working.
+	c.Profiles = parseProfiles(yamlData["pipelines"])
+	if len(c.Profiles) == 0 {
+		c.Profiles = defaultProfiles()
+	}
+
 	return nil
 }
 
+// parseProfiles decodes the "pipelines" YAML block into the profile table.
+func parseProfiles(raw interface{}) map[string]PipelineProfile {
+	block, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	profiles := make(map[string]PipelineProfile, len(block))
+	for name, v := range block {
+		entry, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		p := PipelineProfile{}
+		if sr, ok := entry["sample_rate_hz"].(int); ok {
+			p.SampleRateHz = sr
+		}
+		if f, ok := entry["audiosocket_format"].(string); ok {
+			p.AudioSocketFormat = f
+		}
+		if f, ok := entry["provider_input_format"].(string); ok {
+			p.ProviderInputFormat = f
+		}
+		if f, ok := entry["provider_output_format"].(string); ok {
+			p.ProviderOutputFormat = f
+		}
+		if ch, ok := entry["channels"].(int); ok {
+			p.Channels = ch
+		}
+		if bd, ok := entry["bit_depth"].(int); ok {
+			p.BitDepth = bd
+		}
+		profiles[name] = p
+	}
+	return profiles
+}
+
 // SaveEnv updates .env file in-place
 func (c *Config) SaveEnv() error {
 	// Read existing .env
@@ -254,6 +336,20 @@ func (c *Config) SaveYAML(template string) error {
 	if c.DefaultProvider != "" {
 		yamlData["default_provider"] = c.DefaultProvider
 	}
+	if len(c.Profiles) > 0 {
+		pipelines := make(map[string]interface{}, len(c.Profiles))
+		for name, p := range c.Profiles {
+			pipelines[name] = map[string]interface{}{
+				"sample_rate_hz":         p.SampleRateHz,
+				"audiosocket_format":     p.AudioSocketFormat,
+				"provider_input_format":  p.ProviderInputFormat,
+				"provider_output_format": p.ProviderOutputFormat,
+				"channels":               p.Channels,
+				"bit_depth":              p.BitDepth,
+			}
+		}
+		yamlData["pipelines"] = pipelines
+	}
 
 	// Write back
 	output, err := yaml.Marshal(yamlData)
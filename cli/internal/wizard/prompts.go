@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/fatih/color"
+	"golang.org/x/term"
 )
 
 var (
@@ -38,22 +39,46 @@ func PromptText(label string, defaultVal string) string {
 	return input
 }
 
-// PromptPassword asks for password input (hidden)
+// PromptPassword asks for password input with no-echo entry when stdin is a
+// terminal (golang.org/x/term.ReadPassword). When stdin isn't a TTY (piped
+// input, a test harness), it falls back to a plain bufio read since there's
+// no terminal to suppress echo on anyway.
 func PromptPassword(label string, hasExisting bool) string {
-	reader := bufio.NewReader(os.Stdin)
-	
 	if hasExisting {
 		promptColor.Printf("  %s [unchanged if blank]: ", label)
 	} else {
 		promptColor.Printf("  %s: ", label)
 	}
-	
-	// Note: For production, use terminal.ReadPassword for true hidden input
-	// For now, using basic readline (visible for testing)
+
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		input, err := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Println()
+		if err == nil {
+			return strings.TrimSpace(string(input))
+		}
+		// fall through to the plain reader on a read error
+	}
+
+	reader := bufio.NewReader(os.Stdin)
 	input, _ := reader.ReadString('\n')
-	input = strings.TrimSpace(input)
-	
-	return input
+	return strings.TrimSpace(input)
+}
+
+// PromptNewPassword is PromptPassword plus a confirmation re-entry, for
+// secrets being set for the first time (not an "unchanged if blank" update
+// to an existing one). Returns "" (with a printed warning) if the two
+// entries don't match, so the caller can re-prompt.
+func PromptNewPassword(label string) string {
+	first := PromptPassword(label, false)
+	if first == "" {
+		return ""
+	}
+	confirm := PromptPassword(label+" (confirm)", false)
+	if first != confirm {
+		warningColor.Println("  Entries didn't match, try again")
+		return ""
+	}
+	return first
 }
 
 // PromptSelect shows numbered options and returns selected index (0-based)
@@ -126,6 +151,37 @@ func PrintInfo(msg string) {
 	infoColor.Printf("  ℹ️  %s\n", msg)
 }
 
+// PromptPipelineProfile lets the operator pick an existing audio profile or
+// define a new one for the given pipeline name.
+func PromptPipelineProfile(pipeline string, existing map[string]PipelineProfile) PipelineProfile {
+	if p, ok := existing[pipeline]; ok {
+		infoColor.Printf("  Using existing profile '%s': %d Hz, audiosocket=%s, provider_in=%s, provider_out=%s\n",
+			pipeline, p.SampleRateHz, p.AudioSocketFormat, p.ProviderInputFormat, p.ProviderOutputFormat)
+		if !PromptConfirm("Edit this profile?", false) {
+			return p
+		}
+	}
+
+	fmt.Println()
+	infoColor.Printf("  Configuring audio profile for pipeline '%s'\n", pipeline)
+
+	sampleRate, _ := strconv.Atoi(PromptText("Sample rate (Hz)", "8000"))
+	audioSocketFormat := PromptText("AudioSocket format", "slin")
+	providerInput := PromptText("Provider input format", "linear16")
+	providerOutput := PromptText("Provider output format", "linear16")
+	channels, _ := strconv.Atoi(PromptText("Channels", "1"))
+	bitDepth, _ := strconv.Atoi(PromptText("Bit depth", "16"))
+
+	return PipelineProfile{
+		SampleRateHz:         sampleRate,
+		AudioSocketFormat:    audioSocketFormat,
+		ProviderInputFormat:  providerInput,
+		ProviderOutputFormat: providerOutput,
+		Channels:             channels,
+		BitDepth:             bitDepth,
+	}
+}
+
 // PrintStep prints step header
 func PrintStep(stepNum int, totalSteps int, title string) {
 	fmt.Println()
@@ -0,0 +1,366 @@
+package wizard
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/zalando/go-keyring"
+)
+
+// SecretStore abstracts where operator-entered secrets (API keys, etc.) are
+// persisted. EnvFileStore (the existing .env behavior) remains the default;
+// KeyringStore/VaultStore/SystemdCredStore let an operator opt into a
+// dedicated secret manager instead of plaintext-on-disk.
+type SecretStore interface {
+	// Get returns the secret for key, or ok=false if it isn't set.
+	Get(key string) (value string, ok bool, err error)
+	// Set stores value under key.
+	Set(key, value string) error
+	// Name identifies the backend, used in config's ${secret:key} comments
+	// and in `agent setup` prompts.
+	Name() string
+}
+
+// SecretStoreFactory builds a SecretStore, given backend-specific config
+// (e.g. a Vault address) pulled from the environment.
+type SecretStoreFactory func() (SecretStore, error)
+
+var secretStoreRegistry = map[string]SecretStoreFactory{
+	"env":     func() (SecretStore, error) { return NewEnvFileStore(".env"), nil },
+	"keyring": func() (SecretStore, error) { return NewKeyringStore("asterisk-ai-voice-agent"), nil },
+	"vault":   newVaultStoreFromEnv,
+	"systemd": func() (SecretStore, error) { return NewSystemdCredStore(), nil },
+}
+
+// NewSecretStore builds the named backend ("env", "keyring", "vault",
+// "systemd").
+func NewSecretStore(backend string) (SecretStore, error) {
+	factory, ok := secretStoreRegistry[backend]
+	if !ok {
+		return nil, fmt.Errorf("unknown secret store backend: %s", backend)
+	}
+	return factory()
+}
+
+// SecretRefPrefix is the config placeholder prefix this package resolves,
+// e.g. "${secret:openai_api_key}".
+const SecretRefPrefix = "secret:"
+
+// ResolveSecretRefs replaces every ${secret:key} placeholder in s with the
+// value store.Get(key) returns. A placeholder whose key isn't found in the
+// store is left untouched (rather than erroring), so a config referencing a
+// not-yet-migrated secret still loads — the agent surfaces the missing
+// value the same way a missing plain env var does today.
+func ResolveSecretRefs(s string, store SecretStore) string {
+	var out strings.Builder
+	for {
+		start := strings.Index(s, "${"+SecretRefPrefix)
+		if start == -1 {
+			out.WriteString(s)
+			break
+		}
+		end := strings.Index(s[start:], "}")
+		if end == -1 {
+			out.WriteString(s)
+			break
+		}
+		end += start
+		out.WriteString(s[:start])
+		key := s[start+2+len(SecretRefPrefix) : end]
+		if value, ok, err := store.Get(key); err == nil && ok {
+			out.WriteString(value)
+		} else {
+			out.WriteString(s[start : end+1])
+		}
+		s = s[end+1:]
+	}
+	return out.String()
+}
+
+// ---- env file backend (current behavior) ----
+
+// EnvFileStore persists secrets as KEY=value lines in a .env file, the
+// same format SaveEnv already writes.
+type EnvFileStore struct {
+	path string
+}
+
+func NewEnvFileStore(path string) *EnvFileStore {
+	return &EnvFileStore{path: path}
+}
+
+func (s *EnvFileStore) Name() string { return "env" }
+
+func (s *EnvFileStore) Get(key string) (string, bool, error) {
+	f, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) == 2 && strings.TrimSpace(parts[0]) == key {
+			return strings.TrimSpace(parts[1]), true, nil
+		}
+	}
+	return "", false, scanner.Err()
+}
+
+func (s *EnvFileStore) Set(key, value string) error {
+	var lines []string
+	if data, err := os.ReadFile(s.path); err == nil {
+		lines = strings.Split(string(data), "\n")
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	found := false
+	for i, line := range lines {
+		parts := strings.SplitN(strings.TrimSpace(line), "=", 2)
+		if len(parts) == 2 && parts[0] == key {
+			lines[i] = fmt.Sprintf("%s=%s", key, value)
+			found = true
+			break
+		}
+	}
+	if !found {
+		lines = append(lines, fmt.Sprintf("%s=%s", key, value))
+	}
+	return os.WriteFile(s.path, []byte(strings.Join(lines, "\n")), 0o600)
+}
+
+// ---- OS keyring backend ----
+
+// KeyringStore persists secrets in the OS-native credential store (macOS
+// Keychain, Secret Service on Linux, Windows Credential Manager) via
+// zalando/go-keyring.
+type KeyringStore struct {
+	service string
+}
+
+func NewKeyringStore(service string) *KeyringStore {
+	return &KeyringStore{service: service}
+}
+
+func (s *KeyringStore) Name() string { return "keyring" }
+
+func (s *KeyringStore) Get(key string) (string, bool, error) {
+	value, err := keyring.Get(s.service, key)
+	if err == keyring.ErrNotFound {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return value, true, nil
+}
+
+func (s *KeyringStore) Set(key, value string) error {
+	return keyring.Set(s.service, key, value)
+}
+
+// ---- HashiCorp Vault (KV v2) backend ----
+
+// VaultStore reads/writes secrets from a Vault KV v2 mount over its HTTP
+// API directly (no vendored Vault SDK, consistent with how this CLI talks
+// to other HTTP services like --webhook).
+type VaultStore struct {
+	addr       string
+	token      string
+	mountPath  string // e.g. "secret" for the default KV v2 mount
+	secretPath string // path under the mount, e.g. "asterisk-ai-voice-agent"
+	client     *http.Client
+}
+
+func newVaultStoreFromEnv() (SecretStore, error) {
+	addr := os.Getenv("VAULT_ADDR")
+	token := os.Getenv("VAULT_TOKEN")
+	if addr == "" || token == "" {
+		return nil, fmt.Errorf("VAULT_ADDR and VAULT_TOKEN are required for the vault secret store")
+	}
+	mount := os.Getenv("VAULT_KV_MOUNT")
+	if mount == "" {
+		mount = "secret"
+	}
+	path := os.Getenv("VAULT_SECRET_PATH")
+	if path == "" {
+		path = "asterisk-ai-voice-agent"
+	}
+	return &VaultStore{addr: strings.TrimRight(addr, "/"), token: token, mountPath: mount, secretPath: path, client: &http.Client{}}, nil
+}
+
+func (s *VaultStore) Name() string { return "vault" }
+
+func (s *VaultStore) url() string {
+	return fmt.Sprintf("%s/v1/%s/data/%s", s.addr, s.mountPath, s.secretPath)
+}
+
+type vaultKV2Data struct {
+	Data struct {
+		Data map[string]string `json:"data"`
+	} `json:"data"`
+}
+
+func (s *VaultStore) Get(key string) (string, bool, error) {
+	req, err := http.NewRequest(http.MethodGet, s.url(), nil)
+	if err != nil {
+		return "", false, err
+	}
+	req.Header.Set("X-Vault-Token", s.token)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return "", false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", false, fmt.Errorf("vault GET %s: status %d", s.url(), resp.StatusCode)
+	}
+	var body vaultKV2Data
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", false, err
+	}
+	value, ok := body.Data.Data[key]
+	return value, ok, nil
+}
+
+func (s *VaultStore) Set(key, value string) error {
+	_, existing, err := s.allValues()
+	if err != nil {
+		return err
+	}
+	existing[key] = value
+
+	payload, err := json.Marshal(map[string]interface{}{"data": existing})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, s.url(), bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Vault-Token", s.token)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("vault POST %s: status %d", s.url(), resp.StatusCode)
+	}
+	return nil
+}
+
+func (s *VaultStore) allValues() (bool, map[string]string, error) {
+	req, err := http.NewRequest(http.MethodGet, s.url(), nil)
+	if err != nil {
+		return false, nil, err
+	}
+	req.Header.Set("X-Vault-Token", s.token)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return false, nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return false, map[string]string{}, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, nil, fmt.Errorf("vault GET %s: status %d", s.url(), resp.StatusCode)
+	}
+	var body vaultKV2Data
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return false, nil, err
+	}
+	if body.Data.Data == nil {
+		body.Data.Data = map[string]string{}
+	}
+	return true, body.Data.Data, nil
+}
+
+// ---- systemd credentials backend ----
+
+// SystemdCredStore reads secrets systemd provisioned via LoadCredential= /
+// SetCredential=, exposed to the unit as files under $CREDENTIALS_DIRECTORY.
+// Credentials are provisioned by the unit file, not by this process, so Set
+// always fails with a clear error instead of silently doing nothing.
+type SystemdCredStore struct{}
+
+func NewSystemdCredStore() *SystemdCredStore { return &SystemdCredStore{} }
+
+func (s *SystemdCredStore) Name() string { return "systemd" }
+
+func (s *SystemdCredStore) Get(key string) (string, bool, error) {
+	dir := os.Getenv("CREDENTIALS_DIRECTORY")
+	if dir == "" {
+		return "", false, nil
+	}
+	data, err := os.ReadFile(filepath.Join(dir, key))
+	if os.IsNotExist(err) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return strings.TrimSpace(string(data)), true, nil
+}
+
+func (s *SystemdCredStore) Set(key, value string) error {
+	return fmt.Errorf("systemd credentials are provisioned by the unit file's LoadCredential=/SetCredential=, not written by the agent")
+}
+
+// ChooseSecretBackend prompts the operator to pick a SecretStore backend
+// (first-run setup question). Callers that don't want a prompt (e.g.
+// non-interactive reruns) should read a previously-saved choice instead of
+// calling this again.
+func ChooseSecretBackend() (SecretStore, error) {
+	options := []string{
+		"Plaintext .env file (current default)",
+		"OS keyring (macOS Keychain / Secret Service / Windows Credential Manager)",
+		"HashiCorp Vault (KV v2)",
+		"systemd credentials (read-only; provisioned by the unit file)",
+	}
+	backends := []string{"env", "keyring", "vault", "systemd"}
+	idx := PromptSelect("Where should secrets (API keys) be stored?", options, 0)
+	return NewSecretStore(backends[idx])
+}
+
+// MigrateEnvSecrets copies every key in keys from a .env file at envPath
+// into dst, so an operator switching to keyring/vault/systemd doesn't have
+// to re-type every secret. Keys not present in .env are skipped, not
+// errored, since not every deployment sets every optional provider key.
+func MigrateEnvSecrets(envPath string, dst SecretStore, keys []string) (migrated []string, err error) {
+	src := NewEnvFileStore(envPath)
+	for _, key := range keys {
+		value, ok, err := src.Get(key)
+		if err != nil {
+			return migrated, err
+		}
+		if !ok || value == "" {
+			continue
+		}
+		if err := dst.Set(key, value); err != nil {
+			return migrated, fmt.Errorf("failed to migrate %s to %s: %w", key, dst.Name(), err)
+		}
+		migrated = append(migrated, key)
+	}
+	return migrated, nil
+}